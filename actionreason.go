@@ -0,0 +1,87 @@
+package tfplanparse
+
+import (
+	"strings"
+)
+
+// ActionReason describes why terraform chose the UpdateType of a resource
+// Reasons are only printed by terraform v1.x, so ActionReason is empty for older plans
+type ActionReason string
+
+const (
+	NoActionReason ActionReason = ""
+
+	ReplaceBecauseTainted      ActionReason = "replaceBecauseTainted"
+	ReplaceBecauseCannotUpdate ActionReason = "replaceBecauseCannotUpdate"
+	ReplaceByRequest           ActionReason = "replaceByRequest"
+	ReplaceByTriggers          ActionReason = "replaceByTriggers"
+
+	DeleteBecauseNoResourceConfig ActionReason = "deleteBecauseNoResourceConfig"
+	DeleteBecauseNoModule         ActionReason = "deleteBecauseNoModule"
+	DeleteBecauseWrongRepetition  ActionReason = "deleteBecauseWrongRepetition"
+	DeleteBecauseCountIndex       ActionReason = "deleteBecauseCountIndex"
+	DeleteBecauseEachKey          ActionReason = "deleteBecauseEachKey"
+	DeleteBecauseNoMoveTarget     ActionReason = "deleteBecauseNoMoveTarget"
+
+	ReadBecauseConfigUnknown     ActionReason = "readBecauseConfigUnknown"
+	ReadBecauseDependencyPending ActionReason = "readBecauseDependencyPending"
+	ReadBecauseCheckNested       ActionReason = "readBecauseCheckNested"
+)
+
+const (
+	REASON_BECAUSE            = "because "
+	REASON_CONFIG_UNKNOWN     = "config refers to values not yet known"
+	REASON_NOT_IN_CONFIG      = " is not in configuration"
+	REASON_NO_MOVE_TARGET     = ", which is not in configuration"
+	REASON_COUNT_INDEX        = " is out of range for count"
+	REASON_EACH_KEY           = " is not in for_each map"
+	REASON_DEPENDENCY_PENDING = "depends on a resource or a module with changes pending"
+	REASON_CHECK_NESTED       = "config will be reloaded to verify a check block"
+	REASON_REPLACE_TRIGGERED  = "replace triggered by "
+)
+
+// wrongRepetitionReasons are printed when a resource switched between count, for_each or neither
+var wrongRepetitionReasons = []string{
+	"because resource does not use count",
+	"because resource uses count",
+	"because resource does not use for_each",
+	"because resource uses for_each",
+}
+
+// newActionReasonFromAnnotation returns the ActionReason described by the text of a resource annotation line
+// The text must not contain the surrounding "# (" and ")"
+// Returns NoActionReason if the annotation is not a reason
+func newActionReasonFromAnnotation(text string) ActionReason {
+	for _, r := range wrongRepetitionReasons {
+		if text == r {
+			return DeleteBecauseWrongRepetition
+		}
+	}
+
+	switch {
+	case text == REASON_CONFIG_UNKNOWN:
+		return ReadBecauseConfigUnknown
+	case text == REASON_DEPENDENCY_PENDING:
+		return ReadBecauseDependencyPending
+	case text == REASON_CHECK_NESTED:
+		return ReadBecauseCheckNested
+	case strings.HasPrefix(text, REASON_REPLACE_TRIGGERED):
+		return ReplaceByTriggers
+	case !strings.HasPrefix(text, REASON_BECAUSE):
+		return NoActionReason
+	case strings.HasSuffix(text, REASON_NO_MOVE_TARGET):
+		return DeleteBecauseNoMoveTarget
+	case strings.HasSuffix(text, REASON_NOT_IN_CONFIG):
+		// modules are reported with their module address, resources without it
+		if strings.HasPrefix(strings.TrimPrefix(text, REASON_BECAUSE), "module.") {
+			return DeleteBecauseNoModule
+		}
+		return DeleteBecauseNoResourceConfig
+	case strings.HasSuffix(text, REASON_COUNT_INDEX):
+		return DeleteBecauseCountIndex
+	case strings.HasSuffix(text, REASON_EACH_KEY):
+		return DeleteBecauseEachKey
+	}
+
+	return NoActionReason
+}
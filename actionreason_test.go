@@ -0,0 +1,80 @@
+package tfplanparse
+
+import (
+	"testing"
+)
+
+func TestNewActionReasonFromAnnotation(t *testing.T) {
+	cases := map[string]struct {
+		text     string
+		expected ActionReason
+	}{
+		"empty text": {
+			text:     "",
+			expected: NoActionReason,
+		},
+		"resource not in configuration": {
+			text:     "because aws_instance.a is not in configuration",
+			expected: DeleteBecauseNoResourceConfig,
+		},
+		"data source not in configuration": {
+			text:     "because data.aws_ami.a is not in configuration",
+			expected: DeleteBecauseNoResourceConfig,
+		},
+		"module not in configuration": {
+			text:     `because module.foo["a"] is not in configuration`,
+			expected: DeleteBecauseNoModule,
+		},
+		"resource no longer uses count": {
+			text:     "because resource does not use count",
+			expected: DeleteBecauseWrongRepetition,
+		},
+		"resource now uses for_each": {
+			text:     "because resource uses for_each",
+			expected: DeleteBecauseWrongRepetition,
+		},
+		"index out of range": {
+			text:     "because index [2] is out of range for count",
+			expected: DeleteBecauseCountIndex,
+		},
+		"key not in for_each": {
+			text:     `because key ["x"] is not in for_each map`,
+			expected: DeleteBecauseEachKey,
+		},
+		"move target not in configuration": {
+			text:     "because aws_instance.a was moved to aws_instance.b, which is not in configuration",
+			expected: DeleteBecauseNoMoveTarget,
+		},
+		"config unknown": {
+			text:     "config refers to values not yet known",
+			expected: ReadBecauseConfigUnknown,
+		},
+		"dependency pending": {
+			text:     "depends on a resource or a module with changes pending",
+			expected: ReadBecauseDependencyPending,
+		},
+		"check block": {
+			text:     "config will be reloaded to verify a check block",
+			expected: ReadBecauseCheckNested,
+		},
+		"replace triggered by": {
+			text:     "replace triggered by aws_instance.a",
+			expected: ReplaceByTriggers,
+		},
+		"moved from is not a reason": {
+			text:     "moved from aws_instance.a",
+			expected: NoActionReason,
+		},
+		"unknown reason": {
+			text:     "because of something else",
+			expected: NoActionReason,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := newActionReasonFromAnnotation(tc.text); got != tc.expected {
+				t.Errorf("Expected: %v but got %v", tc.expected, got)
+			}
+		})
+	}
+}
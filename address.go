@@ -0,0 +1,211 @@
+package tfplanparse
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type ResourceMode string
+
+const (
+	// ManagedResourceMode is the mode of resources declared with a "resource" block
+	ManagedResourceMode ResourceMode = "managed"
+	// DataResourceMode is the mode of data sources declared with a "data" block
+	DataResourceMode ResourceMode = "data"
+)
+
+// Address is an absolute resource instance address
+// Example: module.a["eu-west-1"].module.b.data.aws_ami.latest[0]
+type Address struct {
+	// Module contains each module call in the path to the resource, outermost first
+	Module []ModuleInstance
+
+	// Mode is either managed or data
+	Mode ResourceMode
+
+	// Type contains the type of the resource
+	// Example: aws_ami
+	Type string
+
+	// Name contains the name of the resource
+	// Example: latest
+	Name string
+
+	// Key contains the instance key for resources created with "count" or "for_each"
+	// "count" resources will be an int index, and "for_each" will be a string
+	Key interface{}
+}
+
+// ModuleInstance is a single module call in a resource address
+// Example: module.a["eu-west-1"]
+type ModuleInstance struct {
+	Name string
+
+	// Key contains the instance key for modules created with "count" or "for_each"
+	Key interface{}
+}
+
+// addressSegment is a name in an address and the instance key following it, if any
+type addressSegment struct {
+	name string
+	key  interface{}
+}
+
+// ParseAddress creates an Address from an absolute resource instance address
+func ParseAddress(address string) (*Address, error) {
+	segments, err := tokenizeAddress(address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse address %s: %s", address, err)
+	}
+
+	result := &Address{
+		Mode: ManagedResourceMode,
+	}
+
+	// a module call is followed by at least a resource type and name
+	for len(segments) >= 4 && segments[0].name == "module" && segments[0].key == nil {
+		result.Module = append(result.Module, ModuleInstance{
+			Name: segments[1].name,
+			Key:  segments[1].key,
+		})
+		segments = segments[2:]
+	}
+
+	if len(segments) == 3 && segments[0].name == "data" && segments[0].key == nil {
+		result.Mode = DataResourceMode
+		segments = segments[1:]
+	}
+
+	if len(segments) != 2 || segments[0].key != nil {
+		return nil, fmt.Errorf("failed to parse address %s: expected a resource type and name", address)
+	}
+
+	result.Type = segments[0].name
+	result.Name = segments[1].name
+	result.Key = segments[1].key
+
+	return result, nil
+}
+
+// String returns the address in the same format as terraform
+func (a *Address) String() string {
+	var b strings.Builder
+
+	b.WriteString(a.ModuleAddress())
+	if b.Len() > 0 {
+		b.WriteString(".")
+	}
+	if a.Mode == DataResourceMode {
+		b.WriteString("data.")
+	}
+	b.WriteString(a.Type)
+	b.WriteString(".")
+	b.WriteString(a.Name)
+	b.WriteString(formatAddressKey(a.Key))
+
+	return b.String()
+}
+
+// ModuleAddress returns the module portion of the address, or an empty string if the resource is in the root module
+// Example: module.a["eu-west-1"].module.b
+func (a *Address) ModuleAddress() string {
+	parts := []string{}
+	for _, m := range a.Module {
+		parts = append(parts, "module."+m.Name+formatAddressKey(m.Key))
+	}
+
+	return strings.Join(parts, ".")
+}
+
+// tokenizeAddress splits an address into its names and instance keys
+// Keys are quoted strings or ints, and quoted strings may contain "." or "]"
+func tokenizeAddress(address string) ([]addressSegment, error) {
+	result := []addressSegment{}
+	expectName := true
+
+	for i := 0; i < len(address); {
+		switch {
+		case expectName:
+			end := strings.IndexAny(address[i:], ".[]\"")
+			if end == -1 {
+				end = len(address) - i
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("expected a name at position %d", i)
+			}
+			result = append(result, addressSegment{name: address[i : i+end]})
+			i += end
+			expectName = false
+		case address[i] == '.':
+			i++
+			expectName = true
+			if i == len(address) {
+				return nil, fmt.Errorf("expected a name at position %d", i)
+			}
+		case address[i] == '[':
+			last := &result[len(result)-1]
+			if last.key != nil {
+				return nil, fmt.Errorf("unexpected second key at position %d", i)
+			}
+			key, n, err := readAddressKey(address[i:])
+			if err != nil {
+				return nil, err
+			}
+			last.key = key
+			i += n
+		default:
+			return nil, fmt.Errorf("unexpected %q at position %d", address[i], i)
+		}
+	}
+
+	if expectName {
+		return nil, fmt.Errorf("address is empty")
+	}
+
+	return result, nil
+}
+
+// readAddressKey reads an instance key starting with "[", and returns the key and the number of bytes read
+func readAddressKey(s string) (interface{}, int, error) {
+	if strings.HasPrefix(s, "[\"") {
+		for i := 2; i < len(s); i++ {
+			switch s[i] {
+			case '\\':
+				i++
+			case '"':
+				if i+1 >= len(s) || s[i+1] != ']' {
+					return nil, 0, fmt.Errorf("unterminated key %s", s)
+				}
+				key, err := strconv.Unquote(s[1 : i+1])
+				if err != nil {
+					return nil, 0, fmt.Errorf("invalid key %s: %s", s[1:i+1], err)
+				}
+				return key, i + 2, nil
+			}
+		}
+		return nil, 0, fmt.Errorf("unterminated key %s", s)
+	}
+
+	end := strings.Index(s, "]")
+	if end == -1 {
+		return nil, 0, fmt.Errorf("unterminated key %s", s)
+	}
+	key, err := strconv.Atoi(s[1:end])
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid key %s: %s", s[1:end], err)
+	}
+
+	return key, end + 1, nil
+}
+
+func formatAddressKey(key interface{}) string {
+	switch k := key.(type) {
+	case int:
+		return fmt.Sprintf("[%d]", k)
+	case string:
+		return fmt.Sprintf("[%s]", strconv.Quote(k))
+	}
+
+	return ""
+}
@@ -0,0 +1,168 @@
+package tfplanparse
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseAddress(t *testing.T) {
+	cases := map[string]struct {
+		address     string
+		expected    *Address
+		shouldError bool
+	}{
+		"empty address": {
+			address:     "",
+			shouldError: true,
+			expected:    nil,
+		},
+		"resource": {
+			address:     "aws_instance.web",
+			shouldError: false,
+			expected: &Address{
+				Mode: ManagedResourceMode,
+				Type: "aws_instance",
+				Name: "web",
+			},
+		},
+		"data source": {
+			address:     "data.aws_ami.latest",
+			shouldError: false,
+			expected: &Address{
+				Mode: DataResourceMode,
+				Type: "aws_ami",
+				Name: "latest",
+			},
+		},
+		"resource with int key": {
+			address:     "aws_instance.web[1]",
+			shouldError: false,
+			expected: &Address{
+				Mode: ManagedResourceMode,
+				Type: "aws_instance",
+				Name: "web",
+				Key:  1,
+			},
+		},
+		"resource with string key containing dots and brackets": {
+			address:     `aws_instance.web["a.b[0]"]`,
+			shouldError: false,
+			expected: &Address{
+				Mode: ManagedResourceMode,
+				Type: "aws_instance",
+				Name: "web",
+				Key:  "a.b[0]",
+			},
+		},
+		"resource with string key containing an escaped quote": {
+			address:     `aws_instance.web["say \"hi\""]`,
+			shouldError: false,
+			expected: &Address{
+				Mode: ManagedResourceMode,
+				Type: "aws_instance",
+				Name: "web",
+				Key:  `say "hi"`,
+			},
+		},
+		"nested modules with keys": {
+			address:     `module.a["eu-west-1"].module.b[0].aws_s3_bucket.x`,
+			shouldError: false,
+			expected: &Address{
+				Module: []ModuleInstance{
+					{Name: "a", Key: "eu-west-1"},
+					{Name: "b", Key: 0},
+				},
+				Mode: ManagedResourceMode,
+				Type: "aws_s3_bucket",
+				Name: "x",
+			},
+		},
+		"module with data source named data": {
+			address:     "module.mymodule.data.data.data",
+			shouldError: false,
+			expected: &Address{
+				Module: []ModuleInstance{
+					{Name: "mymodule"},
+				},
+				Mode: DataResourceMode,
+				Type: "data",
+				Name: "data",
+			},
+		},
+		"module named module": {
+			address:     "module.module.resource.path",
+			shouldError: false,
+			expected: &Address{
+				Module: []ModuleInstance{
+					{Name: "module"},
+				},
+				Mode: ManagedResourceMode,
+				Type: "resource",
+				Name: "path",
+			},
+		},
+		"missing name": {
+			address:     "aws_instance",
+			shouldError: true,
+			expected:    nil,
+		},
+		"key on the resource type": {
+			address:     "aws_instance[0].web",
+			shouldError: true,
+			expected:    nil,
+		},
+		"unterminated key": {
+			address:     `aws_instance.web["a`,
+			shouldError: true,
+			expected:    nil,
+		},
+		"invalid int key": {
+			address:     "aws_instance.web[a]",
+			shouldError: true,
+			expected:    nil,
+		},
+		"trailing dot": {
+			address:     "aws_instance.web.",
+			shouldError: true,
+			expected:    nil,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParseAddress(tc.address)
+			if err == nil && tc.shouldError {
+				t.Fatalf("Expected an error but didn't get one")
+			}
+			if err != nil && !tc.shouldError {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Fatalf("Expected: %v but got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestAddressString(t *testing.T) {
+	cases := []string{
+		"aws_instance.web",
+		"data.aws_ami.latest",
+		"aws_instance.web[1]",
+		`aws_instance.web["a.b[0]"]`,
+		`aws_instance.web["say \"hi\""]`,
+		`module.a["eu-west-1"].module.b[0].aws_s3_bucket.x`,
+		"module.mymodule.data.data.data",
+	}
+	for _, address := range cases {
+		t.Run(address, func(t *testing.T) {
+			parsed, err := ParseAddress(address)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := parsed.String(); got != address {
+				t.Errorf("Expected: %v but got %v", address, got)
+			}
+		})
+	}
+}
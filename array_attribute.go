@@ -10,62 +10,100 @@ import (
 // Should use an interface
 type ArrayAttributeChange struct {
 	Name             string
-	AttributeChanges []attributeChange
+	AttributeChanges []Attr
 	UpdateType       UpdateType
+
+	// ForcesReplacement indicates whether the change to the attribute forces the resource to be replaced
+	ForcesReplacement bool
+
+	// Kind contains the type of collection
+	// Refer to multiline_attribute.go for possible values
+	Kind CollectionKind
+
+	// Computed indicates whether the whole value after the change is computed during apply
+	// Example: ] -> (known after apply)
+	Computed bool
+
+	// BeforeSensitive and AfterSensitive indicate whether the value is sensitive before and after the change
+	// They are set from a warning that the value changes sensitivity
+	BeforeSensitive bool
+	AfterSensitive  bool
+
+	// Hidden contains the number of unchanged values omitted from the plan output
+	Hidden HiddenCount
 }
 
-var _ attributeChange = &ArrayAttributeChange{}
+var _ Attr = &ArrayAttributeChange{}
 
 // IsArrayAttributeChangeLine returns true if the line is a valid attribute change
 // This requires the line to start with "+", "-" or "~", not be followed with "resource" or "data", and ends with "[".
+// Empty jsonencode values, such as "jsonencode([])", are checked with IsJSONEncodeAttributeChangeLine instead
 func IsArrayAttributeChangeLine(line string) bool {
-	line = strings.TrimSpace(line)
+	line, _ = trimForcesReplacement(line)
 	// validPrefix := strings.HasPrefix(line, "+") || strings.HasPrefix(line, "-") || strings.HasPrefix(line, "~")
 	validSuffix := strings.HasSuffix(line, "[") || IsOneLineEmptyArrayAttribute(line)
-	return validSuffix && !IsResourceChangeLine(line)
+	return validSuffix && !IsResourceChangeLine(line) && !IsJSONEncodeAttributeChangeLine(line)
 }
 
-// IsArrayAttributeTerminator returns true if the line is "]" or "] -> null"
+// IsArrayAttributeTerminator returns true if the line is "]", "])", "]," or any of them followed by " -> null" or " -> (known after apply)"
 func IsArrayAttributeTerminator(line string) bool {
-	return strings.TrimSuffix(strings.TrimSpace(line), " -> null") == "]"
+	line = strings.TrimSuffix(strings.TrimSpace(line), ",")
+	line = strings.TrimSuffix(strings.TrimSuffix(line, " -> null"), ATTRIBUTE_CHANGE_DELIMITER+COMPUTED_VALUE)
+	return line == "]" || line == "])"
 }
 
-// IsOneLineEmptyArrayAttribute returns true if the line ends with a "[]"
+// IsOneLineEmptyArrayAttribute returns true if the line ends with a "[]" or "[])", or with "[]) -> null"
+// A deleted empty array without a wrapper, such as "[] -> null", is read as an attribute with an empty value instead
 func IsOneLineEmptyArrayAttribute(line string) bool {
-	return strings.HasSuffix(line, "[]")
+	line, _ = trimForcesReplacement(line)
+	return strings.HasSuffix(line, "[]") || strings.HasSuffix(line, "[])") || strings.HasSuffix(line, "[]) -> null")
 }
 
 // NewArrayAttributeChangeFromLine initializes an ArrayAttributeChange from a line containing an array attribute change
 // It expects a line that passes the IsArrayAttributeChangeLine check
 func NewArrayAttributeChangeFromLine(line string) (*ArrayAttributeChange, error) {
-	line = strings.TrimSpace(line)
+	line, forcesReplacement := trimForcesReplacement(line)
 	if !IsArrayAttributeChangeLine(line) {
 		return nil, fmt.Errorf("%s is not a valid line to initialize a ArrayAttributeChange", line)
 	}
 
 	attributeName := getMultiLineAttributeName(line)
+	kind := getArrayCollectionKind(line)
 	if strings.HasPrefix(line, "+") {
 		// add
 		return &ArrayAttributeChange{
-			Name:       attributeName,
-			UpdateType: NewResource,
+			Name:              attributeName,
+			UpdateType:        NewResource,
+			Kind:              kind,
+			ForcesReplacement: forcesReplacement,
 		}, nil
 	} else if strings.HasPrefix(line, "-") {
 		// destroy
 		return &ArrayAttributeChange{
-			Name:       attributeName,
-			UpdateType: DestroyResource,
+			Name:              attributeName,
+			UpdateType:        DestroyResource,
+			Kind:              kind,
+			ForcesReplacement: forcesReplacement,
 		}, nil
 	} else if strings.HasPrefix(line, "~") {
 		// replace
+		updateType := UpdateInPlaceResource
+		if forcesReplacement {
+			updateType = ForceReplaceResource
+		}
+
 		return &ArrayAttributeChange{
-			Name:       attributeName,
-			UpdateType: UpdateInPlaceResource,
+			Name:              attributeName,
+			UpdateType:        updateType,
+			Kind:              kind,
+			ForcesReplacement: forcesReplacement,
 		}, nil
 	} else {
 		return &ArrayAttributeChange{
-			Name:       attributeName,
-			UpdateType: NoOpResource,
+			Name:              attributeName,
+			UpdateType:        NoOpResource,
+			Kind:              kind,
+			ForcesReplacement: forcesReplacement,
 		}, nil
 	}
 }
@@ -80,15 +118,29 @@ func (a *ArrayAttributeChange) GetUpdateType() UpdateType {
 	return a.UpdateType
 }
 
-// IsSensitive returns true if the attribute contains a sensitive value
+// IsSensitive returns true if the attribute contains a sensitive value, or is sensitive before or after the change
 func (a *ArrayAttributeChange) IsSensitive() bool {
-	// return m.OldValue == SENSITIVE_VALUE || m.NewValue == SENSITIVE_VALUE
+	if a.BeforeSensitive || a.AfterSensitive {
+		return true
+	}
+	for _, ac := range a.AttributeChanges {
+		if ac.IsSensitive() {
+			return true
+		}
+	}
 	return false
 }
 
-// IsComputed returns true if the attribute contains a computed value
+// IsComputed returns true if the attribute contains a computed value, or the whole value after the change is computed
 func (a *ArrayAttributeChange) IsComputed() bool {
-	// return m.OldValue == COMPUTED_VALUE || m.NewValue == COMPUTED_VALUE
+	if a.Computed {
+		return true
+	}
+	for _, ac := range a.AttributeChanges {
+		if ac.IsComputed() {
+			return true
+		}
+	}
 	return false
 }
 
@@ -97,6 +149,11 @@ func (a *ArrayAttributeChange) IsNoOp() bool {
 	return a.UpdateType == NoOpResource
 }
 
+// GetForcesReplacement returns true if the change to the attribute forces the resource to be replaced
+func (a *ArrayAttributeChange) GetForcesReplacement() bool {
+	return a.ForcesReplacement
+}
+
 func (a *ArrayAttributeChange) GetBefore(opts ...GetBeforeAfterOptions) interface{} {
 	// TODO: ensure the result types are all the same
 	// Currently it is assumed that all changes added are the same type...
@@ -120,6 +177,10 @@ attrs:
 }
 
 func (a *ArrayAttributeChange) GetAfter(opts ...GetBeforeAfterOptions) interface{} {
+	if a.Computed {
+		return UnknownMarker
+	}
+
 	// TODO: same as above
 	result := []interface{}{}
 
@@ -138,3 +199,19 @@ attrs:
 
 	return result
 }
+
+// GetBeforeValue returns the initial value of the attribute as a Value
+func (a *ArrayAttributeChange) GetBeforeValue(opts ...GetBeforeAfterOptions) Value {
+	return ValueOf(a.GetBefore(opts...))
+}
+
+// GetAfterValue returns the planned value of the attribute as a Value
+func (a *ArrayAttributeChange) GetAfterValue(opts ...GetBeforeAfterOptions) Value {
+	return ValueOf(a.GetAfter(opts...))
+}
+
+// Attribute returns the attribute at the path, relative to the array
+// The path must start with an index, such as [0].name
+func (a *ArrayAttributeChange) Attribute(path string) (Attr, error) {
+	return findAttr(a.AttributeChanges, true, path)
+}
@@ -22,6 +22,7 @@ func TestNewArrayAttributeChangeFromLine(t *testing.T) {
 			expected: &ArrayAttributeChange{
 				Name:       "attribute",
 				UpdateType: NewResource,
+				Kind:       ListCollection,
 			},
 		},
 		"attribute created with delimiter": {
@@ -30,6 +31,7 @@ func TestNewArrayAttributeChangeFromLine(t *testing.T) {
 			expected: &ArrayAttributeChange{
 				Name:       "attribute",
 				UpdateType: NewResource,
+				Kind:       ListCollection,
 			},
 		},
 		"attribute deleted": {
@@ -38,6 +40,7 @@ func TestNewArrayAttributeChangeFromLine(t *testing.T) {
 			expected: &ArrayAttributeChange{
 				Name:       "attribute",
 				UpdateType: DestroyResource,
+				Kind:       ListCollection,
 			},
 		},
 		"attribute deleted with delimiter": {
@@ -46,6 +49,7 @@ func TestNewArrayAttributeChangeFromLine(t *testing.T) {
 			expected: &ArrayAttributeChange{
 				Name:       "attribute",
 				UpdateType: DestroyResource,
+				Kind:       ListCollection,
 			},
 		},
 		"attribute changed": {
@@ -54,6 +58,7 @@ func TestNewArrayAttributeChangeFromLine(t *testing.T) {
 			expected: &ArrayAttributeChange{
 				Name:       "attribute",
 				UpdateType: UpdateInPlaceResource,
+				Kind:       ListCollection,
 			},
 		},
 		"attribute changed with delimiter": {
@@ -62,6 +67,7 @@ func TestNewArrayAttributeChangeFromLine(t *testing.T) {
 			expected: &ArrayAttributeChange{
 				Name:       "attribute",
 				UpdateType: UpdateInPlaceResource,
+				Kind:       ListCollection,
 			},
 		},
 		"attribute is unchanged": {
@@ -70,6 +76,7 @@ func TestNewArrayAttributeChangeFromLine(t *testing.T) {
 			expected: &ArrayAttributeChange{
 				Name:       "attribute",
 				UpdateType: NoOpResource,
+				Kind:       ListCollection,
 			},
 		},
 		"attribute with delimiter is unchanged": {
@@ -78,6 +85,7 @@ func TestNewArrayAttributeChangeFromLine(t *testing.T) {
 			expected: &ArrayAttributeChange{
 				Name:       "attribute",
 				UpdateType: NoOpResource,
+				Kind:       ListCollection,
 			},
 		},
 		"unchanged empty array": {
@@ -86,6 +94,7 @@ func TestNewArrayAttributeChangeFromLine(t *testing.T) {
 			expected: &ArrayAttributeChange{
 				Name:       "attribute",
 				UpdateType: NoOpResource,
+				Kind:       ListCollection,
 			},
 		},
 		"resource line": {
@@ -99,6 +108,7 @@ func TestNewArrayAttributeChangeFromLine(t *testing.T) {
 			expected: &ArrayAttributeChange{
 				Name:       "attribute",
 				UpdateType: NewResource,
+				Kind:       ListCollection,
 			},
 		},
 		"one line empty array": {
@@ -107,6 +117,44 @@ func TestNewArrayAttributeChangeFromLine(t *testing.T) {
 			expected: &ArrayAttributeChange{
 				Name:       "attribute",
 				UpdateType: NewResource,
+				Kind:       ListCollection,
+			},
+		},
+		"attribute changed and forces replacement": {
+			line:        `~ attribute = toset([ # forces replacement`,
+			shouldError: false,
+			expected: &ArrayAttributeChange{
+				Name:              "attribute",
+				UpdateType:        ForceReplaceResource,
+				Kind:              SetCollection,
+				ForcesReplacement: true,
+			},
+		},
+		"set created": {
+			line:        `+ attribute = toset([`,
+			shouldError: false,
+			expected: &ArrayAttributeChange{
+				Name:       "attribute",
+				UpdateType: NewResource,
+				Kind:       SetCollection,
+			},
+		},
+		"list changed": {
+			line:        `~ attribute = tolist([`,
+			shouldError: false,
+			expected: &ArrayAttributeChange{
+				Name:       "attribute",
+				UpdateType: UpdateInPlaceResource,
+				Kind:       ListCollection,
+			},
+		},
+		"one line empty set": {
+			line:        `+ attribute = toset([])`,
+			shouldError: false,
+			expected: &ArrayAttributeChange{
+				Name:       "attribute",
+				UpdateType: NewResource,
+				Kind:       SetCollection,
 			},
 		},
 		"one line empty array with no delimiter": {
@@ -115,6 +163,7 @@ func TestNewArrayAttributeChangeFromLine(t *testing.T) {
 			expected: &ArrayAttributeChange{
 				Name:       "attribute",
 				UpdateType: NewResource,
+				Kind:       ListCollection,
 			},
 		},
 		"other line": {
@@ -138,6 +187,57 @@ func TestNewArrayAttributeChangeFromLine(t *testing.T) {
 	}
 }
 
+func TestIsArrayAttributeTerminator(t *testing.T) {
+	cases := map[string]struct {
+		line     string
+		expected bool
+	}{
+		"empty line": {
+			line:     "",
+			expected: false,
+		},
+		"array terminator": {
+			line:     "]",
+			expected: true,
+		},
+		"array terminator deleted": {
+			line:     "] -> null",
+			expected: true,
+		},
+		"wrapped array terminator": {
+			line:     "])",
+			expected: true,
+		},
+		"array terminator computed": {
+			line:     "] -> (known after apply)",
+			expected: true,
+		},
+		"wrapped array terminator deleted": {
+			line:     "]) -> null",
+			expected: true,
+		},
+		"nested array terminator": {
+			line:     "],",
+			expected: true,
+		},
+		"padded with spaces": {
+			line:     "    ])",
+			expected: true,
+		},
+		"map terminator": {
+			line:     "})",
+			expected: false,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := IsArrayAttributeTerminator(tc.line); got != tc.expected {
+				t.Errorf("Expected: %v but got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
 func TestGetBefore(t *testing.T) {
 	cases := map[string]struct {
 		aa       *ArrayAttributeChange
@@ -146,7 +246,7 @@ func TestGetBefore(t *testing.T) {
 	}{
 		"one attribute": {
 			aa: &ArrayAttributeChange{
-				AttributeChanges: []attributeChange{
+				AttributeChanges: []Attr{
 					&AttributeChange{
 						Name:     "attribute",
 						OldValue: "oldValue",
@@ -160,7 +260,7 @@ func TestGetBefore(t *testing.T) {
 		},
 		"multiple attribute": {
 			aa: &ArrayAttributeChange{
-				AttributeChanges: []attributeChange{
+				AttributeChanges: []Attr{
 					&AttributeChange{
 						Name:     "attribute1",
 						OldValue: "oldValue1",
@@ -180,7 +280,7 @@ func TestGetBefore(t *testing.T) {
 		},
 		"ignores new attributes": {
 			aa: &ArrayAttributeChange{
-				AttributeChanges: []attributeChange{
+				AttributeChanges: []Attr{
 					&AttributeChange{
 						Name:     "attribute1",
 						OldValue: "oldValue1",
@@ -200,10 +300,10 @@ func TestGetBefore(t *testing.T) {
 		},
 		"map attribute": {
 			aa: &ArrayAttributeChange{
-				AttributeChanges: []attributeChange{
+				AttributeChanges: []Attr{
 					&MapAttributeChange{
 						Name: "map",
-						AttributeChanges: []attributeChange{
+						AttributeChanges: []Attr{
 							&AttributeChange{
 								Name:     "attribute1",
 								OldValue: "oldValue1",
@@ -227,11 +327,11 @@ func TestGetBefore(t *testing.T) {
 		},
 		"ignore sensitive values": {
 			aa: &ArrayAttributeChange{
-				AttributeChanges: []attributeChange{
+				AttributeChanges: []Attr{
 					&AttributeChange{
 						Name:     "attribute",
-						OldValue: "(sensitive value)",
-						NewValue: "(sensitive value)",
+						OldValue: SensitiveMarker,
+						NewValue: SensitiveMarker,
 					},
 					&AttributeChange{
 						Name:     "attribute2",
@@ -2,7 +2,6 @@ package tfplanparse
 
 import (
 	"fmt"
-	"strconv"
 	"strings"
 )
 
@@ -10,10 +9,17 @@ const (
 	ATTRIBUTE_CHANGE_DELIMITER    = " -> "
 	ATTRIBUTE_DEFINITON_DELIMITER = " = "
 	SENSITIVE_VALUE               = "(sensitive value)"
-	COMPUTED_VALUE                = "(known after apply)"
+	// terraform v0.14+ renders some sensitive values with a shorter marker
+	SENSITIVE_SHORT_VALUE     = "(sensitive)"
+	COMPUTED_VALUE            = "(known after apply)"
+	FORCES_REPLACEMENT_SUFFIX = " # forces replacement"
+	// terraform v0.14+ replaces the contents of blocks containing sensitive values with this comment
+	SENSITIVE_BLOCK_COMMENT = "# At least one attribute in this block is (or was) sensitive,"
 )
 
-type attributeChange interface {
+// Attr is a planned change to an attribute
+// It is implemented by AttributeChange, BlockChange, MapAttributeChange, ArrayAttributeChange, JSONEncodeAttributeChange and HeredocAttributeChange
+type Attr interface {
 	GetName() string
 	GetUpdateType() UpdateType
 	GetBefore(opts ...GetBeforeAfterOptions) interface{}
@@ -21,6 +27,9 @@ type attributeChange interface {
 	IsComputed() bool
 	IsSensitive() bool
 	IsNoOp() bool
+	GetForcesReplacement() bool
+	GetBeforeValue(opts ...GetBeforeAfterOptions) Value
+	GetAfterValue(opts ...GetBeforeAfterOptions) Value
 }
 
 type AttributeChange struct {
@@ -28,14 +37,22 @@ type AttributeChange struct {
 	OldValue   interface{}
 	NewValue   interface{}
 	UpdateType UpdateType
+
+	// ForcesReplacement indicates whether the change to the attribute forces the resource to be replaced
+	ForcesReplacement bool
+
+	// BeforeSensitive and AfterSensitive indicate whether the value is sensitive before and after the change
+	// A value can become sensitive, or stop being sensitive, without its value changing
+	BeforeSensitive bool
+	AfterSensitive  bool
 }
 
-var _ attributeChange = &AttributeChange{}
+var _ Attr = &AttributeChange{}
 
 // IsAttributeChangeLine returns true if the line is a valid attribute change
 // This requires the line to start with "+", "-" or "~", and not be followed with "resource"
 func IsAttributeChangeLine(line string) bool {
-	line = strings.TrimSpace(line)
+	line, _ = trimForcesReplacement(line)
 	attribute := strings.SplitN(removeChangeTypeCharacters(line), ATTRIBUTE_DEFINITON_DELIMITER, 2)
 	// validPrefix := strings.HasPrefix(line, "+") || strings.HasPrefix(line, "-") || strings.HasPrefix(line, "~")
 	multilineAttribute := strings.HasSuffix(line, "(") || strings.HasSuffix(line, "{")
@@ -45,7 +62,7 @@ func IsAttributeChangeLine(line string) bool {
 
 // IsAttributeChangeArrayItem returns true if the line is a valid attribute change in an array
 func IsAttributeChangeArrayItem(line string) bool {
-	line = strings.TrimSpace(line)
+	line, _ = trimForcesReplacement(line)
 	validSuffix := strings.HasSuffix(line, ",")
 	multilineAttribute := strings.HasSuffix(line, "(") || strings.HasSuffix(line, "{")
 
@@ -54,103 +71,140 @@ func IsAttributeChangeArrayItem(line string) bool {
 
 // NewAttributeChangeFromLine initializes an AttributeChange from a line containing an attribute change
 // It expects a line that passes the IsAttributeChangeLine check
-func NewAttributeChangeFromLine(line string) (*AttributeChange, error) {
-	line = strings.TrimSpace(line)
+func NewAttributeChangeFromLine(line string, opts ...ParseOption) (*AttributeChange, error) {
+	return newAttributeChangeFromLine(line, newParseConfig(opts...))
+}
+
+func newAttributeChangeFromLine(line string, cfg *parseConfig) (*AttributeChange, error) {
+	line, forcesReplacement := trimForcesReplacement(line)
 	if !IsAttributeChangeLine(line) {
 		return nil, fmt.Errorf("%s is not a valid line to initialize an attributeChange", line)
 	}
 
-	attribute := strings.SplitN(removeChangeTypeCharacters(line), ATTRIBUTE_DEFINITON_DELIMITER, 2)
+	name, value, err := readAttributeDefinition(line)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read attribute change from line %s: %s", line, err)
+	}
+	before, after, changed, err := readAttributeValues(value, cfg.numberMode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read attribute change from line %s: %s", line, err)
+	}
 
 	if strings.HasPrefix(line, "+") {
 		// add
 		return &AttributeChange{
-			Name:       dequote(strings.TrimSpace(attribute[0])),
-			OldValue:   nil,
-			NewValue:   doTypeConversion(attribute[1]),
-			UpdateType: NewResource,
+			Name:              name,
+			OldValue:          nil,
+			NewValue:          after,
+			UpdateType:        NewResource,
+			ForcesReplacement: forcesReplacement,
+			AfterSensitive:    after == SensitiveMarker,
 		}, nil
 	} else if strings.HasPrefix(line, "-") {
 		// destroy
-		values := strings.Split(attribute[1], ATTRIBUTE_CHANGE_DELIMITER)
-		if len(values) != 2 {
-			return &AttributeChange{
-				Name:       dequote(strings.TrimSpace(attribute[0])),
-				OldValue:   doTypeConversion(strings.TrimSpace(attribute[1])),
-				NewValue:   nil,
-				UpdateType: DestroyResource,
-			}, nil
-		}
-
 		return &AttributeChange{
-			Name:       dequote(strings.TrimSpace(attribute[0])),
-			OldValue:   doTypeConversion(values[0]),
-			NewValue:   nil,
-			UpdateType: DestroyResource,
+			Name:              name,
+			OldValue:          before,
+			NewValue:          nil,
+			UpdateType:        DestroyResource,
+			ForcesReplacement: forcesReplacement,
+			BeforeSensitive:   before == SensitiveMarker,
 		}, nil
 	} else if strings.HasPrefix(line, "~") {
 		// replace
 		updateType := UpdateInPlaceResource
-
-		if strings.HasSuffix(attribute[1], " # forces replacement") {
+		if forcesReplacement {
 			updateType = ForceReplaceResource
-			attribute[1] = strings.TrimSuffix(attribute[1], " # forces replacement")
 		}
 
-		values := strings.Split(attribute[1], ATTRIBUTE_CHANGE_DELIMITER)
-		if len(values) != 2 {
-			if values[0] != SENSITIVE_VALUE {
-				return nil, fmt.Errorf("failed to read attribute change from line %s", line)
-			}
-			values = append(values, SENSITIVE_VALUE)
+		if !changed && before != SensitiveMarker {
+			return nil, fmt.Errorf("failed to read attribute change from line %s", line)
 		}
 
 		return &AttributeChange{
-			Name:       dequote(strings.TrimSpace(attribute[0])),
-			OldValue:   doTypeConversion(values[0]),
-			NewValue:   doTypeConversion(values[1]),
-			UpdateType: updateType,
+			Name:              name,
+			OldValue:          before,
+			NewValue:          after,
+			UpdateType:        updateType,
+			ForcesReplacement: forcesReplacement,
+			BeforeSensitive:   before == SensitiveMarker,
+			AfterSensitive:    after == SensitiveMarker,
 		}, nil
 	} else {
 		return &AttributeChange{
-			Name:       dequote(strings.TrimSpace(attribute[0])),
-			OldValue:   doTypeConversion(attribute[1]),
-			NewValue:   doTypeConversion(attribute[1]),
-			UpdateType: NoOpResource,
+			Name:              name,
+			OldValue:          before,
+			NewValue:          before,
+			UpdateType:        NoOpResource,
+			ForcesReplacement: forcesReplacement,
+			BeforeSensitive:   before == SensitiveMarker,
+			AfterSensitive:    before == SensitiveMarker,
 		}, nil
 	}
 }
 
-// NewAttributeChangeFromLine initializes an AttributeChange from a line within an Array attribute
+// NewAttributeChangeFromArray initializes an AttributeChange from a line within an Array attribute
 // In an array resource, the attribute change does not have a name
-func NewAttributeChangeFromArray(line string) (*AttributeChange, error) {
-	line = strings.TrimSpace(line)
+func NewAttributeChangeFromArray(line string, opts ...ParseOption) (*AttributeChange, error) {
+	return newAttributeChangeFromArray(line, newParseConfig(opts...))
+}
+
+func newAttributeChangeFromArray(line string, cfg *parseConfig) (*AttributeChange, error) {
+	line, forcesReplacement := trimForcesReplacement(line)
 	if line == "" || line == "}" || IsResourceChangeLine(line) {
 		return nil, fmt.Errorf("%s is not a valid line to initialize an attributeChange", line)
 	}
+	before, after, changed, err := readAttributeValues(removeChangeTypeCharacters(line), cfg.numberMode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read attribute change from line %s: %s", line, err)
+	}
+
 	if strings.HasPrefix(line, "+") {
 		// add
 		return &AttributeChange{
-			OldValue:   nil,
-			NewValue:   normalizeArrayAttribute(line),
-			UpdateType: NewResource,
+			OldValue:          nil,
+			NewValue:          after,
+			UpdateType:        NewResource,
+			ForcesReplacement: forcesReplacement,
+			AfterSensitive:    after == SensitiveMarker,
 		}, nil
 	} else if strings.HasPrefix(line, "-") {
 		// destroy
 		return &AttributeChange{
-			OldValue:   normalizeArrayAttribute(line),
-			NewValue:   nil,
-			UpdateType: DestroyResource,
+			OldValue:          before,
+			NewValue:          nil,
+			UpdateType:        DestroyResource,
+			ForcesReplacement: forcesReplacement,
+			BeforeSensitive:   before == SensitiveMarker,
 		}, nil
 	} else if strings.HasPrefix(line, "~") {
 		// replace
-		// TODO: confirm this is possible? I think array entries are immutable
-		return nil, fmt.Errorf("unexpected replace single attribute in array %s", line)
+		// elements of tuples, such as the arrays in jsonencode values, can be changed in place
+		updateType := UpdateInPlaceResource
+		if forcesReplacement {
+			updateType = ForceReplaceResource
+		}
+
+		if !changed && before != SensitiveMarker {
+			return nil, fmt.Errorf("failed to read attribute change from line %s", line)
+		}
+
+		return &AttributeChange{
+			OldValue:          before,
+			NewValue:          after,
+			UpdateType:        updateType,
+			ForcesReplacement: forcesReplacement,
+			BeforeSensitive:   before == SensitiveMarker,
+			AfterSensitive:    after == SensitiveMarker,
+		}, nil
 	} else {
 		return &AttributeChange{
-			OldValue:   normalizeArrayAttribute(line),
-			NewValue:   normalizeArrayAttribute(line),
-			UpdateType: NoOpResource,
+			OldValue:          before,
+			NewValue:          before,
+			UpdateType:        NoOpResource,
+			ForcesReplacement: forcesReplacement,
+			BeforeSensitive:   before == SensitiveMarker,
+			AfterSensitive:    before == SensitiveMarker,
 		}, nil
 	}
 }
@@ -175,14 +229,14 @@ func (a *AttributeChange) GetAfter(opts ...GetBeforeAfterOptions) interface{} {
 	return a.NewValue
 }
 
-// IsSensitive returns true if the attribute contains a sensitive value
+// IsSensitive returns true if the attribute contains a sensitive value, or is sensitive before or after the change
 func (a *AttributeChange) IsSensitive() bool {
-	return a.OldValue == SENSITIVE_VALUE || a.NewValue == SENSITIVE_VALUE
+	return a.OldValue == SensitiveMarker || a.NewValue == SensitiveMarker || a.BeforeSensitive || a.AfterSensitive
 }
 
 // IsComputed returns true if the attribute contains a computed value
 func (a *AttributeChange) IsComputed() bool {
-	return a.OldValue == COMPUTED_VALUE || a.NewValue == COMPUTED_VALUE
+	return a.OldValue == UnknownMarker || a.NewValue == UnknownMarker
 }
 
 // IsNoOp returns true if the attribute has not changed
@@ -190,37 +244,32 @@ func (a *AttributeChange) IsNoOp() bool {
 	return a.UpdateType == NoOpResource
 }
 
-func doTypeConversion(input string) interface{} {
-	// if it has quotes, assume it is a string and return it without quotes
-	if strings.HasPrefix(input, `"`) && strings.HasSuffix(input, `"`) {
-		return dequote(input)
-	}
-
-	if input == "{}" {
-		return nil
-	}
-
-	if input == "true" || input == "false" {
-		b, err := strconv.ParseBool(input)
-		if err != nil {
-			return input
-		}
-		return b
-	}
-
-	if i, err := strconv.Atoi(input); err == nil {
-		return i
-	}
+// GetForcesReplacement returns true if the change to the attribute forces the resource to be replaced
+func (a *AttributeChange) GetForcesReplacement() bool {
+	return a.ForcesReplacement
+}
 
-	if f, err := strconv.ParseFloat(input, 64); err == nil {
-		return f
+// trimForcesReplacement trims the line and removes the " # forces replacement" suffix
+// The returned bool is true if the line had the suffix
+func trimForcesReplacement(line string) (string, bool) {
+	line = strings.TrimSpace(line)
+	if strings.HasSuffix(line, FORCES_REPLACEMENT_SUFFIX) {
+		return strings.TrimSpace(strings.TrimSuffix(line, FORCES_REPLACEMENT_SUFFIX)), true
 	}
+	return line, false
+}
 
-	return input
+// isComputedTerminator returns true if the line terminating a multiline value is followed by " -> (known after apply)"
+// Example: ] -> (known after apply)
+func isComputedTerminator(line string) bool {
+	return strings.HasSuffix(strings.TrimSuffix(strings.TrimSpace(line), ","), ATTRIBUTE_CHANGE_DELIMITER+COMPUTED_VALUE)
 }
 
-func normalizeArrayAttribute(line string) interface{} {
-	return doTypeConversion(strings.TrimRight(removeChangeTypeCharacters(line), ","))
+// isSensitiveTerminator returns true if the line terminating a multiline value is followed by " -> (sensitive value)" or " -> (sensitive)"
+// Example: EOT -> (sensitive value)
+func isSensitiveTerminator(line string) bool {
+	line = strings.TrimSuffix(strings.TrimSpace(line), ",")
+	return strings.HasSuffix(line, ATTRIBUTE_CHANGE_DELIMITER+SENSITIVE_VALUE) || strings.HasSuffix(line, ATTRIBUTE_CHANGE_DELIMITER+SENSITIVE_SHORT_VALUE)
 }
 
 func removeChangeTypeCharacters(line string) string {
@@ -230,3 +279,13 @@ func removeChangeTypeCharacters(line string) string {
 func dequote(line string) string {
 	return strings.TrimPrefix(strings.TrimSuffix(line, "\""), "\"")
 }
+
+// GetBeforeValue returns the initial value of the attribute as a Value
+func (a *AttributeChange) GetBeforeValue(opts ...GetBeforeAfterOptions) Value {
+	return ValueOf(a.GetBefore(opts...))
+}
+
+// GetAfterValue returns the planned value of the attribute as a Value
+func (a *AttributeChange) GetAfterValue(opts ...GetBeforeAfterOptions) Value {
+	return ValueOf(a.GetAfter(opts...))
+}
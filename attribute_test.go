@@ -135,10 +135,22 @@ func TestNewAttributeChangeFromLine(t *testing.T) {
 			line:        `~ attribute = "old" -> "new" # forces replacement`,
 			shouldError: false,
 			expected: &AttributeChange{
-				Name:       "attribute",
-				OldValue:   "old",
-				NewValue:   "new",
-				UpdateType: ForceReplaceResource,
+				Name:              "attribute",
+				OldValue:          "old",
+				NewValue:          "new",
+				UpdateType:        ForceReplaceResource,
+				ForcesReplacement: true,
+			},
+		},
+		"attribute created and forces replacement": {
+			line:        `+ attribute = "new" # forces replacement`,
+			shouldError: false,
+			expected: &AttributeChange{
+				Name:              "attribute",
+				OldValue:          nil,
+				NewValue:          "new",
+				UpdateType:        NewResource,
+				ForcesReplacement: true,
 			},
 		},
 		"attribute changed and value is unknown": {
@@ -147,20 +159,88 @@ func TestNewAttributeChangeFromLine(t *testing.T) {
 			expected: &AttributeChange{
 				Name:       "attribute",
 				OldValue:   "old",
-				NewValue:   "(known after apply)",
+				NewValue:   UnknownMarker,
 				UpdateType: UpdateInPlaceResource,
 			},
 		},
 		"attribute changed and value is sensitive": {
 			line:        `~ attribute = (sensitive value)`,
 			shouldError: false,
+			expected: &AttributeChange{
+				Name:            "attribute",
+				OldValue:        SensitiveMarker,
+				NewValue:        SensitiveMarker,
+				UpdateType:      UpdateInPlaceResource,
+				BeforeSensitive: true,
+				AfterSensitive:  true,
+			},
+		},
+		"attribute created with the short sensitive marker": {
+			line:        `+ attribute = (sensitive)`,
+			shouldError: false,
+			expected: &AttributeChange{
+				Name:           "attribute",
+				OldValue:       nil,
+				NewValue:       SensitiveMarker,
+				UpdateType:     NewResource,
+				AfterSensitive: true,
+			},
+		},
+		"value contains an arrow and a delimiter": {
+			line:        `~ description = "a -> b" -> "x = y"`,
+			shouldError: false,
+			expected: &AttributeChange{
+				Name:       "description",
+				OldValue:   "a -> b",
+				NewValue:   "x = y",
+				UpdateType: UpdateInPlaceResource,
+			},
+		},
+		"value contains escaped quotes": {
+			line:        `- etag = "W/\"etag-0\"" -> null`,
+			shouldError: false,
+			expected: &AttributeChange{
+				Name:       "etag",
+				OldValue:   `W/"etag-0"`,
+				NewValue:   nil,
+				UpdateType: DestroyResource,
+			},
+		},
+		"string with the text of a marker": {
+			line:        `+ attribute = "(known after apply)"`,
+			shouldError: false,
 			expected: &AttributeChange{
 				Name:       "attribute",
-				OldValue:   "(sensitive value)",
-				NewValue:   "(sensitive value)",
+				OldValue:   nil,
+				NewValue:   "(known after apply)",
+				UpdateType: NewResource,
+			},
+		},
+		"quoted name": {
+			line:        `+ "kubernetes.io/name" = "value"`,
+			shouldError: false,
+			expected: &AttributeChange{
+				Name:       "kubernetes.io/name",
+				OldValue:   nil,
+				NewValue:   "value",
+				UpdateType: NewResource,
+			},
+		},
+		"null is parsed as nil": {
+			line:        `~ attribute = null -> "new"`,
+			shouldError: false,
+			expected: &AttributeChange{
+				Name:       "attribute",
+				OldValue:   nil,
+				NewValue:   "new",
 				UpdateType: UpdateInPlaceResource,
 			},
 		},
+		"changed without a new value": {
+			line:        `~ attribute = "old"`,
+			shouldError: true,
+			expected:    nil,
+		},
 		"attribute is unchanged": {
 			line:        `attribute = "old"`,
 			shouldError: false,
@@ -266,7 +346,7 @@ func TestNewAttributeChangeFromArray(t *testing.T) {
 			line:        `- {}`,
 			shouldError: false,
 			expected: &AttributeChange{
-				OldValue:   nil,
+				OldValue:   map[string]interface{}{},
 				NewValue:   nil,
 				UpdateType: DestroyResource,
 			},
@@ -280,6 +360,20 @@ func TestNewAttributeChangeFromArray(t *testing.T) {
 				UpdateType: NoOpResource,
 			},
 		},
+		"attribute updated in place": {
+			line:        `~ "old" -> "new",`,
+			shouldError: false,
+			expected: &AttributeChange{
+				OldValue:   "old",
+				NewValue:   "new",
+				UpdateType: UpdateInPlaceResource,
+			},
+		},
+		"attribute updated in place without a new value": {
+			line:        `~ "old"`,
+			shouldError: true,
+			expected:    nil,
+		},
 		"resource line": {
 			line:        `+ resource "type" "name" {`,
 			shouldError: true,
@@ -0,0 +1,256 @@
+package tfplanparse
+
+import (
+	"fmt"
+	"strings"
+)
+
+// BlockChange is a planned change to a nested block, such as "metadata {"
+// Unlike maps, a resource can contain multiple blocks with the same name
+// Blocks with the same name are collected into a list in the before and after values of the resource or block containing them
+type BlockChange struct {
+	Name             string
+	AttributeChanges []Attr
+	UpdateType       UpdateType
+
+	// ForcesReplacement indicates whether the change to the block forces the resource to be replaced
+	ForcesReplacement bool
+
+	// Sensitive indicates whether the contents of the block are not displayed because they contain a sensitive value
+	// Example: # At least one attribute in this block is (or was) sensitive,
+	Sensitive bool
+
+	// BeforeSensitive and AfterSensitive indicate whether the block is sensitive before and after the change
+	// A block can become sensitive, or stop being sensitive, without its contents changing
+	BeforeSensitive bool
+	AfterSensitive  bool
+
+	// Hidden contains the number of unchanged attributes and blocks omitted from the plan output
+	Hidden HiddenCount
+}
+
+var _ Attr = &BlockChange{}
+
+// IsBlockChangeLine returns true if the line starts a nested block
+// This requires the line to contain the name of the block followed by "{" or "{}", without a "="
+// Example: + ebs_block_device {
+func IsBlockChangeLine(line string) bool {
+	line, _ = trimForcesReplacement(line)
+	line = strings.TrimSuffix(line, " -> null")
+	if IsResourceChangeLine(line) {
+		return false
+	}
+
+	var name string
+	line = removeChangeTypeCharacters(line)
+	switch {
+	case strings.HasSuffix(line, " {"):
+		name = strings.TrimSuffix(line, " {")
+	case strings.HasSuffix(line, " {}"):
+		name = strings.TrimSuffix(line, " {}")
+	default:
+		return false
+	}
+
+	return identifierRegexp.MatchString(name)
+}
+
+// IsOneLineEmptyBlock returns true if the line is a nested block without any attributes
+// Example: timeouts {}
+func IsOneLineEmptyBlock(line string) bool {
+	line, _ = trimForcesReplacement(line)
+	return IsBlockChangeLine(line) && strings.HasSuffix(strings.TrimSuffix(line, " -> null"), "{}")
+}
+
+// IsSensitiveBlockLine returns true if the line is the comment terraform displays in place of the contents of a block containing sensitive values
+func IsSensitiveBlockLine(line string) bool {
+	return strings.TrimSpace(line) == SENSITIVE_BLOCK_COMMENT
+}
+
+// IsBlockTerminator returns true if the line is "}"
+func IsBlockTerminator(line string) bool {
+	return strings.TrimSpace(line) == "}"
+}
+
+// NewBlockChangeFromLine initializes a BlockChange from a line starting a nested block
+// It expects a line that passes the IsBlockChangeLine check
+func NewBlockChangeFromLine(line string) (*BlockChange, error) {
+	line, forcesReplacement := trimForcesReplacement(line)
+	if !IsBlockChangeLine(line) {
+		return nil, fmt.Errorf("%s is not a valid line to initialize a BlockChange", line)
+	}
+
+	name := getMultiLineAttributeName(line)
+	if strings.HasPrefix(line, "+") {
+		// add
+		return &BlockChange{
+			Name:              name,
+			UpdateType:        NewResource,
+			ForcesReplacement: forcesReplacement,
+		}, nil
+	} else if strings.HasPrefix(line, "-") {
+		// destroy
+		return &BlockChange{
+			Name:              name,
+			UpdateType:        DestroyResource,
+			ForcesReplacement: forcesReplacement,
+		}, nil
+	} else if strings.HasPrefix(line, "~") {
+		// replace
+		updateType := UpdateInPlaceResource
+		if forcesReplacement {
+			updateType = ForceReplaceResource
+		}
+
+		return &BlockChange{
+			Name:              name,
+			UpdateType:        updateType,
+			ForcesReplacement: forcesReplacement,
+		}, nil
+	} else {
+		return &BlockChange{
+			Name:              name,
+			UpdateType:        NoOpResource,
+			ForcesReplacement: forcesReplacement,
+		}, nil
+	}
+}
+
+// GetName returns the name of the block
+func (b *BlockChange) GetName() string {
+	return b.Name
+}
+
+// GetUpdateType returns the UpdateType of the block
+func (b *BlockChange) GetUpdateType() UpdateType {
+	return b.UpdateType
+}
+
+// IsSensitive returns true if the block contains a sensitive value, or is sensitive before or after the change
+func (b *BlockChange) IsSensitive() bool {
+	if b.Sensitive || b.BeforeSensitive || b.AfterSensitive {
+		return true
+	}
+	for _, ac := range b.AttributeChanges {
+		if ac.IsSensitive() {
+			return true
+		}
+	}
+	return false
+}
+
+// IsComputed returns true if the block contains a computed value
+func (b *BlockChange) IsComputed() bool {
+	for _, ac := range b.AttributeChanges {
+		if ac.IsComputed() {
+			return true
+		}
+	}
+	return false
+}
+
+// IsNoOp returns true if the block has not changed
+func (b *BlockChange) IsNoOp() bool {
+	return b.UpdateType == NoOpResource
+}
+
+// GetForcesReplacement returns true if the change to the block forces the resource to be replaced
+func (b *BlockChange) GetForcesReplacement() bool {
+	return b.ForcesReplacement
+}
+
+// GetBefore returns the attributes of the block before the change
+// If the contents of the block are not displayed because they are sensitive, SensitiveMarker is returned instead
+func (b *BlockChange) GetBefore(opts ...GetBeforeAfterOptions) interface{} {
+	if b.Sensitive {
+		return SensitiveMarker
+	}
+
+	return getAttrsBefore(b.AttributeChanges, opts...)
+}
+
+// GetAfter returns the attributes of the block after the change
+// If the contents of the block are not displayed because they are sensitive, SensitiveMarker is returned instead
+func (b *BlockChange) GetAfter(opts ...GetBeforeAfterOptions) interface{} {
+	if b.Sensitive {
+		return SensitiveMarker
+	}
+
+	return getAttrsAfter(b.AttributeChanges, opts...)
+}
+
+// GetBeforeValue returns the attributes of the block before the change as a Value
+func (b *BlockChange) GetBeforeValue(opts ...GetBeforeAfterOptions) Value {
+	return ValueOf(b.GetBefore(opts...))
+}
+
+// GetAfterValue returns the attributes of the block after the change as a Value
+func (b *BlockChange) GetAfterValue(opts ...GetBeforeAfterOptions) Value {
+	return ValueOf(b.GetAfter(opts...))
+}
+
+// Attribute returns the attribute at the path, relative to the block
+// Refer to ParsePath for the format of the path
+func (b *BlockChange) Attribute(path string) (Attr, error) {
+	return findAttr(b.AttributeChanges, false, path)
+}
+
+// getAttrsBefore returns the values of the attributes before the change, keyed by name
+// Blocks with the same name are collected into a list, which does not include blocks that are created
+func getAttrsBefore(attrs []Attr, opts ...GetBeforeAfterOptions) map[string]interface{} {
+	result := map[string]interface{}{}
+
+attrs:
+	for _, a := range attrs {
+		for _, opt := range opts {
+			if opt(a) {
+				continue attrs
+			}
+		}
+
+		if b, ok := a.(*BlockChange); ok {
+			blocks, ok := result[b.Name].([]interface{})
+			if !ok {
+				blocks = []interface{}{}
+			}
+			if b.UpdateType != NewResource {
+				blocks = append(blocks, b.GetBefore(opts...))
+			}
+			result[b.Name] = blocks
+			continue
+		}
+		result[a.GetName()] = a.GetBefore(opts...)
+	}
+
+	return result
+}
+
+// getAttrsAfter returns the values of the attributes after the change, keyed by name
+// Blocks with the same name are collected into a list, which does not include blocks that are destroyed
+func getAttrsAfter(attrs []Attr, opts ...GetBeforeAfterOptions) map[string]interface{} {
+	result := map[string]interface{}{}
+
+attrs:
+	for _, a := range attrs {
+		for _, opt := range opts {
+			if opt(a) {
+				continue attrs
+			}
+		}
+
+		if b, ok := a.(*BlockChange); ok {
+			blocks, ok := result[b.Name].([]interface{})
+			if !ok {
+				blocks = []interface{}{}
+			}
+			if b.UpdateType != DestroyResource {
+				blocks = append(blocks, b.GetAfter(opts...))
+			}
+			result[b.Name] = blocks
+			continue
+		}
+		result[a.GetName()] = a.GetAfter(opts...)
+	}
+
+	return result
+}
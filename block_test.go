@@ -0,0 +1,138 @@
+package tfplanparse
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIsBlockChangeLine(t *testing.T) {
+	cases := map[string]struct {
+		line     string
+		expected bool
+	}{
+		"block": {
+			line:     "~ metadata {",
+			expected: true,
+		},
+		"unchanged block": {
+			line:     "metadata {",
+			expected: true,
+		},
+		"empty block": {
+			line:     "timeouts {}",
+			expected: true,
+		},
+		"block forces replacement": {
+			line:     "- ebs_block_device { # forces replacement",
+			expected: true,
+		},
+		"map attribute": {
+			line:     "~ annotations = {",
+			expected: false,
+		},
+		"object in an array": {
+			line:     "+ {",
+			expected: false,
+		},
+		"quoted name": {
+			line:     `+ "metadata" {`,
+			expected: false,
+		},
+		"resource line": {
+			line:     `+ resource "type" "name" {`,
+			expected: false,
+		},
+		"data source line": {
+			line:     `<= data "type" "name" {`,
+			expected: false,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := IsBlockChangeLine(tc.line); got != tc.expected {
+				t.Fatalf("Expected: %v but got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestNewBlockChangeFromLine(t *testing.T) {
+	cases := map[string]struct {
+		line        string
+		expected    *BlockChange
+		shouldError bool
+	}{
+		"empty line": {
+			line:        "",
+			shouldError: true,
+			expected:    nil,
+		},
+		"block created": {
+			line:        `+ attribute {`,
+			shouldError: false,
+			expected: &BlockChange{
+				Name:       "attribute",
+				UpdateType: NewResource,
+			},
+		},
+		"block deleted": {
+			line:        `- attribute {`,
+			shouldError: false,
+			expected: &BlockChange{
+				Name:       "attribute",
+				UpdateType: DestroyResource,
+			},
+		},
+		"block changed": {
+			line:        `~ attribute {`,
+			shouldError: false,
+			expected: &BlockChange{
+				Name:       "attribute",
+				UpdateType: UpdateInPlaceResource,
+			},
+		},
+		"block is unchanged": {
+			line:        `attribute {`,
+			shouldError: false,
+			expected: &BlockChange{
+				Name:       "attribute",
+				UpdateType: NoOpResource,
+			},
+		},
+		"block forces replacement": {
+			line:        `+ attribute { # forces replacement`,
+			shouldError: false,
+			expected: &BlockChange{
+				Name:              "attribute",
+				UpdateType:        NewResource,
+				ForcesReplacement: true,
+			},
+		},
+		"one line empty block": {
+			line:        `+ attribute {}`,
+			shouldError: false,
+			expected: &BlockChange{
+				Name:       "attribute",
+				UpdateType: NewResource,
+			},
+		},
+		"map attribute": {
+			line:        `+ attribute = {`,
+			shouldError: true,
+			expected:    nil,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := NewBlockChangeFromLine(tc.line)
+			if err == nil && tc.shouldError {
+				t.Fatalf("Expected an error but didn't get one")
+			}
+
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Fatalf("Expected: %v but got %v", tc.expected, got)
+			}
+		})
+	}
+}
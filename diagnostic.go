@@ -0,0 +1,207 @@
+package tfplanparse
+
+import (
+	"bufio"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	WARNING_STRING = "Warning: "
+
+	// terraform v0.15+ draws a box around each diagnostic
+	DIAGNOSTIC_BOX_START  = "╷"
+	DIAGNOSTIC_BOX_LINE   = "│"
+	DIAGNOSTIC_BOX_END    = "╵"
+	LEGACY_SEPARATOR_LINE = "------------------------------------------------------------------------"
+)
+
+type DiagnosticSeverity string
+
+const (
+	ErrorSeverity   DiagnosticSeverity = "error"
+	WarningSeverity DiagnosticSeverity = "warning"
+)
+
+type Diagnostic struct {
+	// Severity is either an error or a warning
+	Severity DiagnosticSeverity
+
+	// Summary contains the text following "Error: " or "Warning: "
+	Summary string
+
+	// Detail contains the non-empty lines describing the diagnostic
+	Detail []string
+
+	// Source contains the location the diagnostic refers to, if any
+	Source *DiagnosticSource
+}
+
+type DiagnosticSource struct {
+	Filename string
+	Line     int
+
+	// Context contains the block the location is in, if any
+	// Example: on main.tf line 12, in resource "aws_instance" "web": -> `resource "aws_instance" "web"`
+	Context string
+}
+
+// PlanError is returned when the plan output contains error diagnostics
+type PlanError struct {
+	Diagnostics []*Diagnostic
+}
+
+func (e *PlanError) Error() string {
+	summaries := []string{}
+	for _, d := range e.Diagnostics {
+		summaries = append(summaries, d.Summary)
+	}
+
+	return fmt.Sprintf("plan failed with %d error(s): %s", len(e.Diagnostics), strings.Join(summaries, "; "))
+}
+
+var diagnosticSourceRegexp = regexp.MustCompile(`^on (.+) line (\d+)(?:, in (.+))?:$`)
+var diagnosticSnippetRegexp = regexp.MustCompile(`^\d+:`)
+
+// IsDiagnosticStartLine returns true if the line starts an error or warning
+// Example: Error: Invalid reference
+func IsDiagnosticStartLine(line string) bool {
+	line = trimDiagnosticBox(line)
+	return strings.HasPrefix(line, ERROR_STRING) || strings.HasPrefix(line, WARNING_STRING)
+}
+
+// NewDiagnosticFromLine creates a Diagnostic from a valid diagnostic start line
+func NewDiagnosticFromLine(line string) (*Diagnostic, error) {
+	line = trimDiagnosticBox(line)
+	if strings.HasPrefix(line, ERROR_STRING) {
+		return &Diagnostic{
+			Severity: ErrorSeverity,
+			Summary:  strings.TrimSpace(strings.TrimPrefix(line, ERROR_STRING)),
+			Detail:   []string{},
+		}, nil
+	} else if strings.HasPrefix(line, WARNING_STRING) {
+		return &Diagnostic{
+			Severity: WarningSeverity,
+			Summary:  strings.TrimSpace(strings.TrimPrefix(line, WARNING_STRING)),
+			Detail:   []string{},
+		}, nil
+	}
+
+	return nil, fmt.Errorf("%s is not a valid line to initialize a Diagnostic", line)
+}
+
+// NewDiagnosticSourceFromLine creates a DiagnosticSource from a line describing the location of a diagnostic
+// Example: on main.tf line 12, in resource "aws_instance" "web":
+func NewDiagnosticSourceFromLine(line string) (*DiagnosticSource, error) {
+	line = trimDiagnosticBox(line)
+	matches := diagnosticSourceRegexp.FindStringSubmatch(line)
+	if matches == nil {
+		return nil, fmt.Errorf("%s is not a valid line to initialize a DiagnosticSource", line)
+	}
+
+	lineNumber, err := strconv.Atoi(matches[2])
+	if err != nil {
+		return nil, fmt.Errorf("failed to read diagnostic source from line %s: %s", line, err)
+	}
+
+	return &DiagnosticSource{
+		Filename: matches[1],
+		Line:     lineNumber,
+		Context:  matches[3],
+	}, nil
+}
+
+// IsError returns true if the diagnostic is an error
+func (d *Diagnostic) IsError() bool {
+	return d.Severity == ErrorSeverity
+}
+
+// IsWarning returns true if the diagnostic is a warning
+func (d *Diagnostic) IsWarning() bool {
+	return d.Severity == WarningSeverity
+}
+
+// parseDiagnostic parses a diagnostic starting at the current line of the scanner
+// Diagnostics from terraform v0.12 to v0.14 have no terminator, so they end at the first line that
+// belongs to the rest of the plan. In that case, the returned bool is true and the caller must process
+// the current line of the scanner.
+func parseDiagnostic(s *bufio.Scanner) (*Diagnostic, bool, error) {
+	start := formatInput(s.Bytes())
+	boxed := strings.HasPrefix(start, DIAGNOSTIC_BOX_LINE)
+	result, err := NewDiagnosticFromLine(start)
+	if err != nil {
+		return nil, false, err
+	}
+
+	for s.Scan() {
+		raw := formatInput(s.Bytes())
+		if boxed && strings.HasPrefix(raw, DIAGNOSTIC_BOX_END) {
+			return result, false, nil
+		}
+		if !boxed && isDiagnosticTerminator(raw) {
+			return result, true, nil
+		}
+
+		text := trimDiagnosticBox(raw)
+		switch {
+		case text == "":
+			continue
+		case result.Source == nil && len(result.Detail) == 0 && isDiagnosticSubjectLine(text):
+			// terraform v1.x names the object the diagnostic refers to before the source
+			continue
+		case result.Source == nil && len(result.Detail) == 0 && diagnosticSourceRegexp.MatchString(text):
+			source, err := NewDiagnosticSourceFromLine(text)
+			if err != nil {
+				return nil, false, err
+			}
+			result.Source = source
+		case result.Source != nil && len(result.Detail) == 0 && isDiagnosticSnippetLine(text):
+			// the source snippet and the values of expressions in it are not part of the detail
+			continue
+		default:
+			result.Detail = append(result.Detail, text)
+		}
+	}
+
+	if boxed {
+		return nil, false, fmt.Errorf("unexpected end of input while parsing diagnostic")
+	}
+
+	return result, false, nil
+}
+
+// isDiagnosticTerminator returns true if the line ends a diagnostic without a box
+func isDiagnosticTerminator(line string) bool {
+	return IsDiagnosticStartLine(line) ||
+		IsChangesStartLine(line) ||
+		IsOutputsStartLine(line) ||
+		IsNoChangesLine(line) ||
+		IsPlanSummaryLine(line) ||
+		IsResourceCommentLine(line) ||
+		detectDialect(line) != UnknownDialect ||
+		strings.HasPrefix(line, LEGACY_SEPARATOR_LINE)
+}
+
+// isDiagnosticSubjectLine returns true if the line names the object a diagnostic refers to
+// Example: with aws_s3_bucket.logs,
+func isDiagnosticSubjectLine(line string) bool {
+	return strings.HasPrefix(line, "with ") && strings.HasSuffix(line, ",")
+}
+
+func isDiagnosticSnippetLine(line string) bool {
+	return diagnosticSnippetRegexp.MatchString(line) ||
+		strings.HasPrefix(line, "├") ||
+		strings.HasPrefix(line, "|") ||
+		strings.HasPrefix(line, DIAGNOSTIC_BOX_LINE)
+}
+
+// trimDiagnosticBox removes the box drawn around diagnostics by terraform v0.15+
+func trimDiagnosticBox(line string) string {
+	line = strings.TrimSpace(line)
+	if line == DIAGNOSTIC_BOX_START {
+		return ""
+	}
+	return strings.TrimSpace(strings.TrimPrefix(line, DIAGNOSTIC_BOX_LINE))
+}
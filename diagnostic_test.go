@@ -0,0 +1,102 @@
+package tfplanparse
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIsDiagnosticStartLine(t *testing.T) {
+	cases := map[string]struct {
+		line     string
+		expected bool
+	}{
+		"empty line": {
+			line:     "",
+			expected: false,
+		},
+		"error": {
+			line:     "Error: Invalid reference",
+			expected: true,
+		},
+		"warning": {
+			line:     "Warning: Argument is deprecated",
+			expected: true,
+		},
+		"boxed error": {
+			line:     "│ Error: Invalid reference",
+			expected: true,
+		},
+		"sensitive attribute warning": {
+			line:     "# Warning: this attribute value will be marked as sensitive and will not",
+			expected: false,
+		},
+		"other line": {
+			line:     "+ error = \"Error: something\"",
+			expected: false,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := IsDiagnosticStartLine(tc.line); got != tc.expected {
+				t.Errorf("Expected: %v but got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestNewDiagnosticSourceFromLine(t *testing.T) {
+	cases := map[string]struct {
+		line        string
+		expected    *DiagnosticSource
+		shouldError bool
+	}{
+		"empty line": {
+			line:        "",
+			shouldError: true,
+			expected:    nil,
+		},
+		"source with context": {
+			line:        `on main.tf line 12, in resource "aws_instance" "web":`,
+			shouldError: false,
+			expected: &DiagnosticSource{
+				Filename: "main.tf",
+				Line:     12,
+				Context:  `resource "aws_instance" "web"`,
+			},
+		},
+		"source without context": {
+			line:        `on modules/vpc/main.tf line 1:`,
+			shouldError: false,
+			expected: &DiagnosticSource{
+				Filename: "modules/vpc/main.tf",
+				Line:     1,
+			},
+		},
+		"boxed source": {
+			line:        `│   on main.tf line 3, in variable "instance_count":`,
+			shouldError: false,
+			expected: &DiagnosticSource{
+				Filename: "main.tf",
+				Line:     3,
+				Context:  `variable "instance_count"`,
+			},
+		},
+		"other line": {
+			line:        "12:   subnet_id = aws_subnet.missing.id",
+			shouldError: true,
+			expected:    nil,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := NewDiagnosticSourceFromLine(tc.line)
+			if err == nil && tc.shouldError {
+				t.Fatalf("Expected an error but didn't get one")
+			}
+
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Fatalf("Expected: %v but got %v", tc.expected, got)
+			}
+		})
+	}
+}
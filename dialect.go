@@ -0,0 +1,46 @@
+package tfplanparse
+
+import (
+	"strings"
+)
+
+type Dialect string
+
+const (
+	UnknownDialect Dialect = "unknown"
+	// LegacyTerraformDialect is the output format of terraform v0.12 to v0.14
+	LegacyTerraformDialect Dialect = "terraform-legacy"
+	// TerraformDialect is the output format of terraform v0.15 and v1.x
+	TerraformDialect Dialect = "terraform"
+	OpenTofuDialect  Dialect = "opentofu"
+)
+
+const (
+	LEGACY_CHANGES_PREAMBLE_STRING = "An execution plan has been generated and is shown below."
+	// The preamble is word wrapped by terraform, so only match the start of the sentence
+	CHANGES_PREAMBLE_STRING           = "Terraform used the selected providers to generate the following"
+	OPENTOFU_CHANGES_PREAMBLE_STRING  = "OpenTofu used the selected providers to generate the following"
+	NO_CHANGES_DETAIL_STRING          = "Terraform has compared your real infrastructure against your configuration"
+	OPENTOFU_NO_CHANGES_DETAIL_STRING = "OpenTofu has compared your real infrastructure against your configuration"
+)
+
+// detectDialect returns the dialect identified by the line, or UnknownDialect if the line
+// is not specific to a single dialect
+func detectDialect(line string) Dialect {
+	switch {
+	case strings.Contains(line, OPENTOFU_CHANGES_PREAMBLE_STRING),
+		strings.Contains(line, OPENTOFU_CHANGES_START_STRING),
+		strings.Contains(line, OPENTOFU_NO_CHANGES_DETAIL_STRING):
+		return OpenTofuDialect
+	case strings.Contains(line, CHANGES_PREAMBLE_STRING),
+		strings.Contains(line, NO_CHANGES_STRING_V1),
+		strings.Contains(line, NO_CHANGES_DRIFT_STRING),
+		strings.Contains(line, NO_CHANGES_DETAIL_STRING):
+		return TerraformDialect
+	case strings.Contains(line, LEGACY_CHANGES_PREAMBLE_STRING),
+		strings.Contains(line, NO_CHANGES_STRING):
+		return LegacyTerraformDialect
+	}
+
+	return UnknownDialect
+}
@@ -0,0 +1,69 @@
+package tfplanparse
+
+// AttributeDiff is a planned change to a single value of a resource
+type AttributeDiff struct {
+	// Path is the path to the value from the root of the resource
+	Path Path
+
+	Before     interface{}
+	After      interface{}
+	UpdateType UpdateType
+
+	Computed  bool
+	Sensitive bool
+
+	// BeforeSensitive and AfterSensitive indicate whether the value is sensitive before and after the change
+	BeforeSensitive bool
+	AfterSensitive  bool
+
+	// ForcesReplacement indicates whether the change to the value, or to an attribute containing the value, forces the resource to be replaced
+	ForcesReplacement bool
+}
+
+// Diff returns the changed values of the resource in the order they appear in the plan output
+// Blocks, maps, arrays and jsonencode values are not included themselves, but each of their changed values is
+// Empty maps and arrays, collections computed during apply, jsonencode values that are computed or sensitive as a whole, and heredoc values are included as a single value
+func (rc *ResourceChange) Diff() []*AttributeDiff {
+	result := []*AttributeDiff{}
+	steps := childSteps(rc.AttributeChanges, false)
+	for i, a := range rc.AttributeChanges {
+		result = append(result, diffAttr(Path(steps[i]), a, false)...)
+	}
+
+	return result
+}
+
+func diffAttr(path Path, a Attr, forcesReplacement bool) []*AttributeDiff {
+	forcesReplacement = forcesReplacement || a.GetForcesReplacement()
+
+	// collections that are computed or sensitive as a whole are a single value
+	children, indexed := childAttrs(a)
+	if len(children) == 0 || a.GetAfter() == UnknownMarker || a.GetAfter() == SensitiveMarker {
+		if a.IsNoOp() {
+			return nil
+		}
+
+		beforeSensitive, afterSensitive := getSensitivity(a)
+		return []*AttributeDiff{
+			&AttributeDiff{
+				Path:              path,
+				Before:            a.GetBefore(),
+				After:             a.GetAfter(),
+				UpdateType:        a.GetUpdateType(),
+				Computed:          a.IsComputed(),
+				Sensitive:         a.IsSensitive(),
+				BeforeSensitive:   beforeSensitive,
+				AfterSensitive:    afterSensitive,
+				ForcesReplacement: forcesReplacement,
+			},
+		}
+	}
+
+	result := []*AttributeDiff{}
+	steps := childSteps(children, indexed)
+	for i, child := range children {
+		result = append(result, diffAttr(path.child(steps[i]...), child, forcesReplacement)...)
+	}
+
+	return result
+}
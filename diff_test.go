@@ -0,0 +1,52 @@
+package tfplanparse
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestResourceChangeDiff(t *testing.T) {
+	cases := map[string]struct {
+		file     string
+		expected []*AttributeDiff
+	}{
+		"forces replacement": {
+			file: "test/forcesreplacement.stdout",
+			expected: []*AttributeDiff{
+				&AttributeDiff{Path: Path{"ami"}, Before: "ami-old", After: "ami-new", UpdateType: ForceReplaceResource, ForcesReplacement: true},
+				&AttributeDiff{Path: Path{"id"}, Before: "i-0123456789abcdef0", After: UnknownMarker, UpdateType: UpdateInPlaceResource, Computed: true},
+				&AttributeDiff{Path: Path{"placement_group"}, After: "web", UpdateType: NewResource, ForcesReplacement: true},
+				&AttributeDiff{Path: Path{"security_groups", 0}, Before: "sg-old", UpdateType: DestroyResource, ForcesReplacement: true},
+				&AttributeDiff{Path: Path{"security_groups", 0}, After: "sg-new", UpdateType: NewResource, ForcesReplacement: true},
+				&AttributeDiff{Path: Path{"tags", "Name"}, Before: "old", After: "new", UpdateType: UpdateInPlaceResource},
+				&AttributeDiff{Path: Path{"ebs_block_device", 0, "device_name"}, Before: "/dev/sdf", UpdateType: DestroyResource, ForcesReplacement: true},
+				&AttributeDiff{Path: Path{"ebs_block_device", 0, "volume_size"}, Before: 10, UpdateType: DestroyResource, ForcesReplacement: true},
+				&AttributeDiff{Path: Path{"ebs_block_device", 0, "device_name"}, After: "/dev/sdf", UpdateType: NewResource, ForcesReplacement: true},
+				&AttributeDiff{Path: Path{"ebs_block_device", 0, "volume_size"}, After: 20, UpdateType: NewResource, ForcesReplacement: true},
+				&AttributeDiff{Path: Path{"root_block_device", 0, "volume_size"}, Before: 8, After: 16, UpdateType: ForceReplaceResource, ForcesReplacement: true},
+			},
+		},
+		"unchanged values are omitted": {
+			file: "test/nestedmap.stdout",
+			expected: []*AttributeDiff{
+				&AttributeDiff{Path: Path{"metadata", 0, "labels", "newLabel"}, After: "newLabel", UpdateType: NewResource},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParsePlanFromFile(tc.file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got.ResourceChanges) != 1 {
+				t.Fatalf("Expected 1 resource change but got %d", len(got.ResourceChanges))
+			}
+
+			if diff := cmp.Diff(got.ResourceChanges[0].Diff(), tc.expected); diff != "" {
+				t.Errorf("(-got, +expected)\n%s", diff)
+			}
+		})
+	}
+}
@@ -5,42 +5,72 @@ import (
 	"strings"
 )
 
+const (
+	// HEREDOC_INDENT is the number of columns between the change symbol of a heredoc attribute and the change symbol of each of its lines
+	HEREDOC_INDENT = 4
+	// HEREDOC_LINE_SYMBOL_WIDTH is the width of the change symbol at the start of each line of a heredoc, such as "+ "
+	HEREDOC_LINE_SYMBOL_WIDTH = 2
+)
+
+// HeredocLine is a single line of a heredoc attribute
+// UpdateType is NewResource for an added line, DestroyResource for a removed line, and NoOpResource for an unchanged line
+type HeredocLine struct {
+	Text       string
+	UpdateType UpdateType
+}
+
 type HeredocAttributeChange struct {
 	Name       string
 	Before     []string
 	After      []string
 	UpdateType UpdateType
+
+	// ForcesReplacement indicates whether the change to the attribute forces the resource to be replaced
+	ForcesReplacement bool
+
+	// Computed indicates whether the value after the change is computed during apply
+	// Example: EOT -> (known after apply)
+	Computed bool
+
+	// Sensitive indicates whether the value after the change is sensitive, and is not displayed
+	// Example: EOT -> (sensitive value)
+	Sensitive bool
+
+	lines []HeredocLine
 }
 
-var _ attributeChange = &HeredocAttributeChange{}
+var _ Attr = &HeredocAttributeChange{}
 
 // IsHeredocAttributeChangeLine returns true if the line is a valid attribute change
 // This requires the line to start with "+", "-" or "~", delimited with a space, and the value to start with "<<".
 func IsHeredocAttributeChangeLine(line string) bool {
-	line = strings.TrimSpace(line)
+	line, _ = trimForcesReplacement(line)
 	attribute := strings.SplitN(line, ATTRIBUTE_DEFINITON_DELIMITER, 2)
 	if len(attribute) != 2 {
 		return false
 	}
 
 	validPrefix := strings.HasPrefix(line, "+") || strings.HasPrefix(line, "-") || strings.HasPrefix(line, "~")
-	// The only permitted heredoc string is <<~EOT
+	// The only permitted heredoc strings are <<~EOT, and <<-EOT from terraform v0.15+
 	// Ref: https://github.com/hashicorp/terraform/blob/6a126df0c601ab23689171506bfc1386fea4c96c/command/format/diff.go#L838
-	isHeredoc := strings.HasPrefix(attribute[1], "<<~EOT")
+	isHeredoc := strings.HasPrefix(attribute[1], "<<~EOT") || strings.HasPrefix(attribute[1], "<<-EOT")
 	return validPrefix && isHeredoc && !IsResourceChangeLine(line)
 }
 
-// IsHeredocAttributeTerminator returns true if the line is "EOT"
+// IsHeredocAttributeTerminator returns true if the line is "EOT", optionally followed by " -> null", " -> (known after apply)", " -> (sensitive value)" or " -> (sensitive)"
 // EOT is the only possible terminator for the heredoc
 // Ref: https://github.com/hashicorp/terraform/blob/6a126df0c601ab23689171506bfc1386fea4c96c/command/format/diff.go#L880
 func IsHeredocAttributeTerminator(line string) bool {
-	return strings.TrimSuffix(strings.TrimSpace(line), " -> null") == "EOT"
+	line = strings.TrimSuffix(strings.TrimSpace(line), " -> null")
+	line = strings.TrimSuffix(line, ATTRIBUTE_CHANGE_DELIMITER+COMPUTED_VALUE)
+	line = strings.TrimSuffix(line, ATTRIBUTE_CHANGE_DELIMITER+SENSITIVE_VALUE)
+	return strings.TrimSuffix(line, ATTRIBUTE_CHANGE_DELIMITER+SENSITIVE_SHORT_VALUE) == "EOT"
 }
 
 // NewHeredocAttributeChangeFromLine initializes a HeredocAttributeChange from a line containing a heredoc change
 // It expects a line that passes the IsHeredocAttributeChangeLine check
 func NewHeredocAttributeChangeFromLine(line string) (*HeredocAttributeChange, error) {
-	line = strings.TrimSpace(line)
+	line, forcesReplacement := trimForcesReplacement(line)
 	if !IsHeredocAttributeChangeLine(line) {
 		return nil, fmt.Errorf("%s is not a valid line to initialize a HeredocAttributeChange", line)
 	}
@@ -49,49 +79,92 @@ func NewHeredocAttributeChangeFromLine(line string) (*HeredocAttributeChange, er
 	if strings.HasPrefix(line, "+") {
 		// add
 		return &HeredocAttributeChange{
-			Name:       dequote(strings.TrimSpace(attribute[0])),
-			Before:     []string{},
-			After:      []string{},
-			UpdateType: NewResource,
+			Name:              dequote(strings.TrimSpace(attribute[0])),
+			Before:            []string{},
+			After:             []string{},
+			UpdateType:        NewResource,
+			ForcesReplacement: forcesReplacement,
 		}, nil
 	} else if strings.HasPrefix(line, "-") {
 		// destroy
 		return &HeredocAttributeChange{
-			Name:       dequote(strings.TrimSpace(attribute[0])),
-			Before:     []string{},
-			After:      []string{},
-			UpdateType: DestroyResource,
+			Name:              dequote(strings.TrimSpace(attribute[0])),
+			Before:            []string{},
+			After:             []string{},
+			UpdateType:        DestroyResource,
+			ForcesReplacement: forcesReplacement,
 		}, nil
 	} else if strings.HasPrefix(line, "~") {
 		// replace
 		updateType := UpdateInPlaceResource
-		if strings.HasSuffix(attribute[1], " # forces replacement") {
+		if forcesReplacement {
 			updateType = ForceReplaceResource
 		}
 
 		return &HeredocAttributeChange{
-			Name:       dequote(strings.TrimSpace(attribute[0])),
-			Before:     []string{},
-			After:      []string{},
-			UpdateType: updateType,
+			Name:              dequote(strings.TrimSpace(attribute[0])),
+			Before:            []string{},
+			After:             []string{},
+			UpdateType:        updateType,
+			ForcesReplacement: forcesReplacement,
 		}, nil
 	} else {
 		return nil, fmt.Errorf("unrecognized line pattern")
 	}
 }
 
+// AddLineToContent adds a line of the heredoc to the before and after values
+// It expects the line without the indentation of the heredoc, starting with the change symbol of the line
+// Example: + echo hello
 func (h *HeredocAttributeChange) AddLineToContent(line string) {
+	text := trimLineSymbol(line)
+	updateType := NoOpResource
+	switch {
+	case strings.HasPrefix(line, "+ ") || line == "+":
+		updateType = NewResource
+	case strings.HasPrefix(line, "- ") || line == "-":
+		updateType = DestroyResource
+	}
+
+	// every line of a created or destroyed heredoc is added or removed, even if terraform displays it without a symbol
 	switch h.UpdateType {
 	case NewResource:
-		h.After = append(h.After, strings.TrimPrefix(line, "+ "))
+		updateType = NewResource
+	case DestroyResource:
+		updateType = DestroyResource
+	}
+
+	switch updateType {
+	case NewResource:
+		h.After = append(h.After, text)
 	case DestroyResource:
-		h.Before = append(h.Before, strings.TrimPrefix(line, "- "))
+		h.Before = append(h.Before, text)
 	default:
-		// replace
-		// TODO: remove ANSI coded - or + only
-		h.Before = append(h.Before, line)
-		h.After = append(h.After, line)
+		h.Before = append(h.Before, text)
+		h.After = append(h.After, text)
 	}
+	h.lines = append(h.lines, HeredocLine{Text: text, UpdateType: updateType})
+}
+
+// Lines returns every line of the heredoc in the order they appear in the plan output, with whether the line was added, removed or unchanged
+func (h *HeredocAttributeChange) Lines() []HeredocLine {
+	return h.lines
+}
+
+// trimLineSymbol removes the change symbol, or the spaces in place of it, from the start of a heredoc line
+func trimLineSymbol(line string) string {
+	end := HEREDOC_LINE_SYMBOL_WIDTH
+	if len(line) < end {
+		end = len(line)
+	}
+
+	switch strings.TrimRight(line[:end], " ") {
+	case "", "+", "-":
+		return line[end:]
+	}
+
+	// the line is not indented as expected
+	return line
 }
 
 // GetName returns the name of the attribute
@@ -104,14 +177,14 @@ func (h *HeredocAttributeChange) GetUpdateType() UpdateType {
 	return h.UpdateType
 }
 
-// IsSensitive returns true if the attribute contains a sensitive value
+// IsSensitive returns true if the value after the change is sensitive
 func (h *HeredocAttributeChange) IsSensitive() bool {
-	return false
+	return h.Sensitive
 }
 
-// IsComputed returns true if the attribute contains a computed value
+// IsComputed returns true if the value after the change is computed
 func (h *HeredocAttributeChange) IsComputed() bool {
-	return false
+	return h.Computed
 }
 
 // IsNoOp returns true if the attribute has not changed
@@ -119,10 +192,32 @@ func (h *HeredocAttributeChange) IsNoOp() bool {
 	return h.UpdateType == NoOpResource
 }
 
+// GetForcesReplacement returns true if the change to the attribute forces the resource to be replaced
+func (h *HeredocAttributeChange) GetForcesReplacement() bool {
+	return h.ForcesReplacement
+}
+
 func (h *HeredocAttributeChange) GetBefore(opts ...GetBeforeAfterOptions) interface{} {
 	return strings.Join(h.Before, "\n")
 }
 
 func (h *HeredocAttributeChange) GetAfter(opts ...GetBeforeAfterOptions) interface{} {
+	if h.Computed {
+		return UnknownMarker
+	}
+	if h.Sensitive {
+		return SensitiveMarker
+	}
+
 	return strings.Join(h.After, "\n")
 }
+
+// GetBeforeValue returns the initial value of the attribute as a Value
+func (h *HeredocAttributeChange) GetBeforeValue(opts ...GetBeforeAfterOptions) Value {
+	return ValueOf(h.GetBefore(opts...))
+}
+
+// GetAfterValue returns the planned value of the attribute as a Value
+func (h *HeredocAttributeChange) GetAfterValue(opts ...GetBeforeAfterOptions) Value {
+	return ValueOf(h.GetAfter(opts...))
+}
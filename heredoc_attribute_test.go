@@ -50,10 +50,11 @@ func TestNewHeredocAttributeChangeFromLine(t *testing.T) {
 			line:        `~ attribute = <<~EOT # forces replacement`,
 			shouldError: false,
 			expected: &HeredocAttributeChange{
-				Name:       "attribute",
-				Before:     []string{},
-				After:      []string{},
-				UpdateType: ForceReplaceResource,
+				Name:              "attribute",
+				Before:            []string{},
+				After:             []string{},
+				UpdateType:        ForceReplaceResource,
+				ForcesReplacement: true,
 			},
 		},
 		"attribute is unchanged": {
@@ -76,6 +77,16 @@ func TestNewHeredocAttributeChangeFromLine(t *testing.T) {
 				UpdateType: NewResource,
 			},
 		},
+		"dash heredoc": {
+			line:        `~ attribute = <<-EOT`,
+			shouldError: false,
+			expected: &HeredocAttributeChange{
+				Name:       "attribute",
+				Before:     []string{},
+				After:      []string{},
+				UpdateType: UpdateInPlaceResource,
+			},
+		},
 		"resource line": {
 			line:        `+ resource "type" "name" {`,
 			shouldError: true,
@@ -111,3 +122,120 @@ func TestNewHeredocAttributeChangeFromLine(t *testing.T) {
 		})
 	}
 }
+
+func TestIsHeredocAttributeTerminator(t *testing.T) {
+	cases := map[string]struct {
+		line     string
+		expected bool
+	}{
+		"terminator":                    {line: "EOT", expected: true},
+		"terminator padded with spaces": {line: "    EOT", expected: true},
+		"deleted":                       {line: "EOT -> null", expected: true},
+		"computed":                      {line: "EOT -> (known after apply)", expected: true},
+		"sensitive":                     {line: "EOT -> (sensitive value)", expected: true},
+		"sensitive short":               {line: "EOT -> (sensitive)", expected: true},
+		"content":                       {line: "EOT is the end", expected: false},
+		"other line":                    {line: "]", expected: false},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := IsHeredocAttributeTerminator(tc.line); got != tc.expected {
+				t.Fatalf("Expected: %v but got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestAddLineToContent(t *testing.T) {
+	cases := map[string]struct {
+		updateType     UpdateType
+		lines          []string
+		expectedBefore string
+		expectedAfter  string
+		expectedLines  []HeredocLine
+	}{
+		"lines changed": {
+			updateType: UpdateInPlaceResource,
+			lines: []string{
+				"  spec:",
+				"-   replicas: 1",
+				"+   replicas: 2",
+				"",
+				"+ ",
+			},
+			expectedBefore: "spec:\n  replicas: 1\n",
+			expectedAfter:  "spec:\n  replicas: 2\n\n",
+			expectedLines: []HeredocLine{
+				{Text: "spec:", UpdateType: NoOpResource},
+				{Text: "  replicas: 1", UpdateType: DestroyResource},
+				{Text: "  replicas: 2", UpdateType: NewResource},
+				{Text: "", UpdateType: NoOpResource},
+				{Text: "", UpdateType: NewResource},
+			},
+		},
+		"heredoc created": {
+			updateType: NewResource,
+			lines: []string{
+				"  items:",
+				"    - a",
+				"  - b",
+			},
+			expectedBefore: "",
+			expectedAfter:  "items:\n  - a\n- b",
+			expectedLines: []HeredocLine{
+				{Text: "items:", UpdateType: NewResource},
+				{Text: "  - a", UpdateType: NewResource},
+				{Text: "- b", UpdateType: NewResource},
+			},
+		},
+		"heredoc destroyed": {
+			updateType: DestroyResource,
+			lines: []string{
+				"- #!/bin/bash",
+				"  echo hello",
+			},
+			expectedBefore: "#!/bin/bash\necho hello",
+			expectedAfter:  "",
+			expectedLines: []HeredocLine{
+				{Text: "#!/bin/bash", UpdateType: DestroyResource},
+				{Text: "echo hello", UpdateType: DestroyResource},
+			},
+		},
+		"line not indented": {
+			updateType: UpdateInPlaceResource,
+			lines: []string{
+				"-1",
+			},
+			expectedBefore: "-1",
+			expectedAfter:  "-1",
+			expectedLines: []HeredocLine{
+				{Text: "-1", UpdateType: NoOpResource},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			h := &HeredocAttributeChange{
+				Name:       "attribute",
+				Before:     []string{},
+				After:      []string{},
+				UpdateType: tc.updateType,
+			}
+			for _, line := range tc.lines {
+				h.AddLineToContent(line)
+			}
+
+			if got := h.GetBefore(); got != tc.expectedBefore {
+				t.Fatalf("Expected before: %q but got %q", tc.expectedBefore, got)
+			}
+			if got := h.GetAfter(); got != tc.expectedAfter {
+				t.Fatalf("Expected after: %q but got %q", tc.expectedAfter, got)
+			}
+			if !reflect.DeepEqual(h.Lines(), tc.expectedLines) {
+				t.Fatalf("Expected lines: %v but got %v", tc.expectedLines, h.Lines())
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package tfplanparse
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// HiddenCount contains the number of unchanged values terraform collapsed into a comment line
+// If any of the counts are non-zero, the before and after values of the container are partial
+type HiddenCount struct {
+	Attributes int
+	Blocks     int
+	Elements   int
+}
+
+var unchangedHiddenRegexp = regexp.MustCompile(`^#\s*\((\d+) unchanged (attribute|block|element)s? hidden\)$`)
+
+// IsUnchangedHiddenLine returns true if the line is a comment describing hidden unchanged values
+// Example: # (3 unchanged attributes hidden)
+func IsUnchangedHiddenLine(line string) bool {
+	return unchangedHiddenRegexp.MatchString(strings.TrimSpace(line))
+}
+
+// NewHiddenCountFromLine creates a HiddenCount from a valid unchanged hidden line
+func NewHiddenCountFromLine(line string) (*HiddenCount, error) {
+	matches := unchangedHiddenRegexp.FindStringSubmatch(strings.TrimSpace(line))
+	if matches == nil {
+		return nil, fmt.Errorf("%s is not a valid line to initialize a HiddenCount", line)
+	}
+
+	count, err := strconv.Atoi(matches[1])
+	if err != nil {
+		return nil, fmt.Errorf("failed to read hidden count from line %s: %s", line, err)
+	}
+
+	switch matches[2] {
+	case "attribute":
+		return &HiddenCount{Attributes: count}, nil
+	case "block":
+		return &HiddenCount{Blocks: count}, nil
+	default:
+		return &HiddenCount{Elements: count}, nil
+	}
+}
+
+// Total returns the total number of hidden values
+func (h HiddenCount) Total() int {
+	return h.Attributes + h.Blocks + h.Elements
+}
+
+// addFromLine adds the counts from a valid unchanged hidden line
+func (h *HiddenCount) addFromLine(line string) error {
+	other, err := NewHiddenCountFromLine(line)
+	if err != nil {
+		return err
+	}
+
+	h.Attributes += other.Attributes
+	h.Blocks += other.Blocks
+	h.Elements += other.Elements
+	return nil
+}
@@ -0,0 +1,77 @@
+package tfplanparse
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewHiddenCountFromLine(t *testing.T) {
+	cases := map[string]struct {
+		line        string
+		expected    *HiddenCount
+		shouldError bool
+	}{
+		"empty line": {
+			line:        "",
+			shouldError: true,
+			expected:    nil,
+		},
+		"attributes hidden": {
+			line:        "# (3 unchanged attributes hidden)",
+			shouldError: false,
+			expected: &HiddenCount{
+				Attributes: 3,
+			},
+		},
+		"one attribute hidden": {
+			line:        "# (1 unchanged attribute hidden)",
+			shouldError: false,
+			expected: &HiddenCount{
+				Attributes: 1,
+			},
+		},
+		"blocks hidden": {
+			line:        "# (2 unchanged blocks hidden)",
+			shouldError: false,
+			expected: &HiddenCount{
+				Blocks: 2,
+			},
+		},
+		"elements hidden": {
+			line:        "# (1 unchanged element hidden)",
+			shouldError: false,
+			expected: &HiddenCount{
+				Elements: 1,
+			},
+		},
+		"padded with spaces": {
+			line:        "      # (10 unchanged attributes hidden)",
+			shouldError: false,
+			expected: &HiddenCount{
+				Attributes: 10,
+			},
+		},
+		"resource comment": {
+			line:        "# resource.path will be created",
+			shouldError: true,
+			expected:    nil,
+		},
+		"other comment": {
+			line:        "# (config refers to values not yet known)",
+			shouldError: true,
+			expected:    nil,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := NewHiddenCountFromLine(tc.line)
+			if err == nil && tc.shouldError {
+				t.Fatalf("Expected an error but didn't get one")
+			}
+
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Fatalf("Expected: %v but got %v", tc.expected, got)
+			}
+		})
+	}
+}
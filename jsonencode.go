@@ -1,73 +1,184 @@
 package tfplanparse
 
 import (
+	"encoding/json"
 	"fmt"
 	"strings"
 )
 
+const (
+	JSONENCODE_PREFIX = "jsonencode("
+)
+
 type JSONEncodeAttributeChange struct {
 	Name             string
-	AttributeChanges []attributeChange
+	AttributeChanges []Attr
 	UpdateType       UpdateType
+
+	// ForcesReplacement indicates whether the change to the attribute forces the resource to be replaced
+	ForcesReplacement bool
+
+	// Computed indicates whether the value after the change is computed during apply
+	// Example: ) -> (known after apply)
+	Computed bool
+
+	// Sensitive indicates whether the value after the change is sensitive, and is not displayed
+	// Example: ) -> (sensitive value)
+	Sensitive bool
+
+	// Hidden contains the number of unchanged values omitted from the plan output
+	Hidden HiddenCount
 }
 
-var _ attributeChange = &JSONEncodeAttributeChange{}
+var _ Attr = &JSONEncodeAttributeChange{}
 
 // IsJSONEncodeAttributeChangeLine returns true if the line is a valid attribute change
 // This requires the line to start with "+", "-" or "~", delimited with a space, and the value to start with "jsonencode(".
 func IsJSONEncodeAttributeChangeLine(line string) bool {
-	line = strings.TrimSpace(line)
+	line, _ = trimForcesReplacement(line)
 	attribute := strings.SplitN(line, ATTRIBUTE_DEFINITON_DELIMITER, 2)
 	if len(attribute) != 2 {
 		return false
 	}
 
 	validPrefix := strings.HasPrefix(line, "+") || strings.HasPrefix(line, "-") || strings.HasPrefix(line, "~")
-	isJSONEncode := strings.HasPrefix(attribute[1], "jsonencode(")
+	isJSONEncode := strings.HasPrefix(attribute[1], JSONENCODE_PREFIX)
 	return validPrefix && isJSONEncode && !IsResourceChangeLine(line)
 }
 
-// IsJSONEncodeAttributeTerminator returns true if the line is ")"
-// TODO: verify this
+// IsJSONEncodeAttributeTerminator returns true if the line is ")", optionally followed by " -> null", " -> (known after apply)", " -> (sensitive value)" or " -> (sensitive)"
 func IsJSONEncodeAttributeTerminator(line string) bool {
-	return strings.TrimSuffix(strings.TrimSpace(line), " -> null") == ")"
+	line = strings.TrimSuffix(strings.TrimSpace(line), " -> null")
+	if isComputedTerminator(line) || isSensitiveTerminator(line) {
+		line = strings.TrimSpace(line[:strings.Index(line, ATTRIBUTE_CHANGE_DELIMITER)])
+	}
+	return line == ")"
+}
+
+// IsOneLineJSONEncodeAttribute returns true if the whole jsonencode value is on the same line, optionally followed by " -> null"
+// terraform displays values that are not objects or arrays, and empty objects and arrays, on one line
+// Example: + policy = jsonencode("x")
+func IsOneLineJSONEncodeAttribute(line string) bool {
+	line, _ = trimForcesReplacement(line)
+	line = strings.TrimSuffix(line, " -> null")
+	return IsJSONEncodeAttributeChangeLine(line) && strings.HasSuffix(line, ")")
+}
+
+// IsJSONEncodeValueLine returns true if the line is a jsonencode value that is not an object or an array
+// The value must be a quoted string, a number, true, false, null, "(known after apply)" or "(sensitive value)"
+// Example: ~ "old" -> "new"
+func IsJSONEncodeValueLine(line string) bool {
+	line, _ = trimForcesReplacement(line)
+	value := removeChangeTypeCharacters(line)
+	if value == "" || IsJSONEncodeAttributeTerminator(value) {
+		return false
+	}
+
+	rest, ok := readJSONScalar(value)
+	if !ok {
+		return false
+	}
+	delimiter := strings.TrimSpace(ATTRIBUTE_CHANGE_DELIMITER)
+	if rest = strings.TrimSpace(rest); strings.HasPrefix(rest, delimiter) {
+		if rest, ok = readJSONScalar(strings.TrimPrefix(rest, delimiter)); !ok {
+			return false
+		}
+		rest = strings.TrimSpace(rest)
+	}
+
+	return rest == "" || rest == ","
+}
+
+// readJSONScalar reads a JSON value that is not an object or an array, or a Marker, from the start of the input, and returns the remaining input
+// readValue returns tokens it does not understand, such as "}" or "EOT", as strings, so they are rejected unless they are quoted
+func readJSONScalar(input string) (string, bool) {
+	input = strings.TrimLeft(input, " ")
+	v, rest, err := readValue(input, ExactNumbers)
+	if err != nil {
+		return "", false
+	}
+
+	switch v.(type) {
+	case nil, bool, Number, Marker:
+		return rest, true
+	case string:
+		return rest, strings.HasPrefix(input, `"`)
+	}
+
+	return "", false
 }
 
 // NewJSONEncodeAttributeChangeFromLine initializes a JSONEncodeAttributeChange from a line containing a JSONEncode change
 // It expects a line that passes the IsJSONEncodeAttributeChangeLine check
-func NewJSONEncodeAttributeChangeFromLine(line string) (*JSONEncodeAttributeChange, error) {
-	line = strings.TrimSpace(line)
+// If the whole value is on the line, it is read into the AttributeChanges of the result
+func NewJSONEncodeAttributeChangeFromLine(line string, opts ...ParseOption) (*JSONEncodeAttributeChange, error) {
+	return newJSONEncodeAttributeChangeFromLine(line, newParseConfig(opts...))
+}
+
+func newJSONEncodeAttributeChangeFromLine(line string, cfg *parseConfig) (*JSONEncodeAttributeChange, error) {
+	line, forcesReplacement := trimForcesReplacement(line)
 	if !IsJSONEncodeAttributeChangeLine(line) {
 		return nil, fmt.Errorf("%s is not a valid line to initialize a JSONEncodeAttributeChange", line)
 	}
 	attribute := strings.SplitN(removeChangeTypeCharacters(line), ATTRIBUTE_DEFINITON_DELIMITER, 2)
 
+	result := &JSONEncodeAttributeChange{
+		Name:              dequote(strings.TrimSpace(attribute[0])),
+		ForcesReplacement: forcesReplacement,
+	}
 	if strings.HasPrefix(line, "+") {
 		// add
-		return &JSONEncodeAttributeChange{
-			Name:       dequote(strings.TrimSpace(attribute[0])),
-			UpdateType: NewResource,
-		}, nil
+		result.UpdateType = NewResource
 	} else if strings.HasPrefix(line, "-") {
 		// destroy
-		return &JSONEncodeAttributeChange{
-			Name:       dequote(strings.TrimSpace(attribute[0])),
-			UpdateType: DestroyResource,
-		}, nil
+		result.UpdateType = DestroyResource
 	} else if strings.HasPrefix(line, "~") {
 		// replace
-		updateType := UpdateInPlaceResource
-		if strings.HasSuffix(attribute[1], " # forces replacement") {
-			updateType = ForceReplaceResource
+		result.UpdateType = UpdateInPlaceResource
+		if forcesReplacement {
+			result.UpdateType = ForceReplaceResource
 		}
-
-		return &JSONEncodeAttributeChange{
-			Name:       dequote(strings.TrimSpace(attribute[0])),
-			UpdateType: updateType,
-		}, nil
 	} else {
 		return nil, fmt.Errorf("unrecognized line pattern")
 	}
+
+	if IsOneLineJSONEncodeAttribute(line) {
+		before, after, _, err := readAttributeValues(attribute[1], cfg.numberMode)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read jsonencode value from line %s: %s", line, err)
+		}
+		result.AttributeChanges = []Attr{newJSONEncodeValue(result.UpdateType, before, after)}
+	}
+
+	return result, nil
+}
+
+// newJSONEncodeValueFromLine initializes the value of a jsonencode attribute from a line that passes the IsJSONEncodeValueLine check
+func newJSONEncodeValueFromLine(line string, updateType UpdateType, cfg *parseConfig) (*AttributeChange, error) {
+	line, _ = trimForcesReplacement(line)
+	before, after, _, err := readAttributeValues(removeChangeTypeCharacters(line), cfg.numberMode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read jsonencode value from line %s: %s", line, err)
+	}
+
+	return newJSONEncodeValue(updateType, before, after), nil
+}
+
+// newJSONEncodeValue initializes the unnamed attribute holding a jsonencode value that is not an object or an array
+// The value is the whole jsonencode value, so it has the same UpdateType as the jsonencode attribute
+func newJSONEncodeValue(updateType UpdateType, before, after interface{}) *AttributeChange {
+	switch updateType {
+	case NewResource:
+		before = nil
+	case DestroyResource:
+		after = nil
+	}
+
+	return &AttributeChange{
+		OldValue:   before,
+		NewValue:   after,
+		UpdateType: updateType,
+	}
 }
 
 // GetName returns the name of the attribute
@@ -80,8 +191,11 @@ func (j *JSONEncodeAttributeChange) GetUpdateType() UpdateType {
 	return j.UpdateType
 }
 
-// IsSensitive returns true if the attribute contains a sensitive value
+// IsSensitive returns true if the attribute contains a sensitive value, or the value after the change is sensitive
 func (j *JSONEncodeAttributeChange) IsSensitive() bool {
+	if j.Sensitive {
+		return true
+	}
 	for _, ac := range j.AttributeChanges {
 		if ac.IsSensitive() {
 			return true
@@ -90,8 +204,11 @@ func (j *JSONEncodeAttributeChange) IsSensitive() bool {
 	return false
 }
 
-// IsComputed returns true if the attribute contains a computed value
+// IsComputed returns true if the attribute contains a computed value, or the value after the change is computed
 func (j *JSONEncodeAttributeChange) IsComputed() bool {
+	if j.Computed {
+		return true
+	}
 	for _, ac := range j.AttributeChanges {
 		if ac.IsComputed() {
 			return true
@@ -105,7 +222,21 @@ func (j *JSONEncodeAttributeChange) IsNoOp() bool {
 	return j.UpdateType == NoOpResource
 }
 
+// GetForcesReplacement returns true if the change to the attribute forces the resource to be replaced
+func (j *JSONEncodeAttributeChange) GetForcesReplacement() bool {
+	return j.ForcesReplacement
+}
+
+// GetBefore returns the decoded value of the attribute before the change, or nil if the attribute is created
+// Refer to GetBeforeJSON for the value as JSON text
 func (j *JSONEncodeAttributeChange) GetBefore(opts ...GetBeforeAfterOptions) interface{} {
+	if j.UpdateType == NewResource {
+		return nil
+	}
+	if v, ok := j.value(); ok {
+		return v.GetBefore(opts...)
+	}
+
 	result := map[string]interface{}{}
 
 attrs:
@@ -121,7 +252,23 @@ attrs:
 	return result
 }
 
+// GetAfter returns the decoded value of the attribute after the change, or nil if the attribute is destroyed
+// If the value after the change is computed or sensitive, UnknownMarker or SensitiveMarker is returned instead
+// Refer to GetAfterJSON for the value as JSON text
 func (j *JSONEncodeAttributeChange) GetAfter(opts ...GetBeforeAfterOptions) interface{} {
+	if j.UpdateType == DestroyResource {
+		return nil
+	}
+	if j.Computed {
+		return UnknownMarker
+	}
+	if j.Sensitive {
+		return SensitiveMarker
+	}
+	if v, ok := j.value(); ok {
+		return v.GetAfter(opts...)
+	}
+
 	result := map[string]interface{}{}
 
 attrs:
@@ -136,3 +283,106 @@ attrs:
 
 	return result
 }
+
+// GetBeforeJSON returns the value of the attribute before the change as JSON text
+// An error is returned if the value contains a computed or sensitive value, since it can't be encoded
+func (j *JSONEncodeAttributeChange) GetBeforeJSON(opts ...GetBeforeAfterOptions) (string, error) {
+	return encodeJSON(j.GetBefore(opts...))
+}
+
+// GetAfterJSON returns the value of the attribute after the change as JSON text
+// An error is returned if the value contains a computed or sensitive value, since it can't be encoded
+func (j *JSONEncodeAttributeChange) GetAfterJSON(opts ...GetBeforeAfterOptions) (string, error) {
+	return encodeJSON(j.GetAfter(opts...))
+}
+
+// UnmarshalBefore decodes the value of the attribute before the change into v, in the same way as json.Unmarshal
+func (j *JSONEncodeAttributeChange) UnmarshalBefore(v interface{}, opts ...GetBeforeAfterOptions) error {
+	text, err := j.GetBeforeJSON(opts...)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal([]byte(text), v)
+}
+
+// UnmarshalAfter decodes the value of the attribute after the change into v, in the same way as json.Unmarshal
+func (j *JSONEncodeAttributeChange) UnmarshalAfter(v interface{}, opts ...GetBeforeAfterOptions) error {
+	text, err := j.GetAfterJSON(opts...)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal([]byte(text), v)
+}
+
+// value returns the attribute holding the decoded value, which is parsed as a single attribute without a name
+func (j *JSONEncodeAttributeChange) value() (Attr, bool) {
+	if len(j.AttributeChanges) != 1 || j.AttributeChanges[0].GetName() != "" {
+		return nil, false
+	}
+
+	return j.AttributeChanges[0], true
+}
+
+// encodeJSON returns the decoded value of a jsonencode attribute as JSON text
+func encodeJSON(v interface{}) (string, error) {
+	if err := checkEncodable(v); err != nil {
+		return "", err
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode jsonencode value: %s", err)
+	}
+
+	return string(b), nil
+}
+
+// checkEncodable returns an error if the value contains a Marker in place of a value that is not shown in the plan output
+func checkEncodable(v interface{}) error {
+	switch value := v.(type) {
+	case Marker:
+		return fmt.Errorf("failed to encode jsonencode value: value is %s", value)
+	case map[string]interface{}:
+		for _, child := range value {
+			if err := checkEncodable(child); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for _, child := range value {
+			if err := checkEncodable(child); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// setJSONCollectionKind marks the arrays and maps in a jsonencode value as tuples and objects, which are the types of JSON values
+func setJSONCollectionKind(a Attr) {
+	switch ac := a.(type) {
+	case *ArrayAttributeChange:
+		ac.Kind = TupleCollection
+		for _, child := range ac.AttributeChanges {
+			setJSONCollectionKind(child)
+		}
+	case *MapAttributeChange:
+		ac.Kind = ObjectCollection
+		for _, child := range ac.AttributeChanges {
+			setJSONCollectionKind(child)
+		}
+	}
+}
+
+// GetBeforeValue returns the initial value of the attribute as a Value
+func (j *JSONEncodeAttributeChange) GetBeforeValue(opts ...GetBeforeAfterOptions) Value {
+	return ValueOf(j.GetBefore(opts...))
+}
+
+// GetAfterValue returns the planned value of the attribute as a Value
+func (j *JSONEncodeAttributeChange) GetAfterValue(opts ...GetBeforeAfterOptions) Value {
+	return ValueOf(j.GetAfter(opts...))
+}
@@ -0,0 +1,193 @@
+package tfplanparse
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewJSONEncodeAttributeChangeFromLine(t *testing.T) {
+	cases := map[string]struct {
+		line        string
+		expected    *JSONEncodeAttributeChange
+		shouldError bool
+	}{
+		"empty line": {
+			line:        "",
+			shouldError: true,
+			expected:    nil,
+		},
+		"attribute created": {
+			line:        `+ policy = jsonencode(`,
+			shouldError: false,
+			expected: &JSONEncodeAttributeChange{
+				Name:       "policy",
+				UpdateType: NewResource,
+			},
+		},
+		"attribute changed and forced replacement": {
+			line:        `~ policy = jsonencode( # forces replacement`,
+			shouldError: false,
+			expected: &JSONEncodeAttributeChange{
+				Name:              "policy",
+				UpdateType:        ForceReplaceResource,
+				ForcesReplacement: true,
+			},
+		},
+		"one line value created": {
+			line:        `+ value = jsonencode("x")`,
+			shouldError: false,
+			expected: &JSONEncodeAttributeChange{
+				Name: "value",
+				AttributeChanges: []Attr{
+					&AttributeChange{
+						OldValue:   nil,
+						NewValue:   "x",
+						UpdateType: NewResource,
+					},
+				},
+				UpdateType: NewResource,
+			},
+		},
+		"one line value changed": {
+			line:        `~ value = jsonencode(1) -> jsonencode(2)`,
+			shouldError: false,
+			expected: &JSONEncodeAttributeChange{
+				Name: "value",
+				AttributeChanges: []Attr{
+					&AttributeChange{
+						OldValue:   1,
+						NewValue:   2,
+						UpdateType: UpdateInPlaceResource,
+					},
+				},
+				UpdateType: UpdateInPlaceResource,
+			},
+		},
+		"one line empty array deleted": {
+			line:        `- value = jsonencode([]) -> null`,
+			shouldError: false,
+			expected: &JSONEncodeAttributeChange{
+				Name: "value",
+				AttributeChanges: []Attr{
+					&AttributeChange{
+						OldValue:   []interface{}{},
+						NewValue:   nil,
+						UpdateType: DestroyResource,
+					},
+				},
+				UpdateType: DestroyResource,
+			},
+		},
+		"malformed one line value": {
+			line:        `+ value = jsonencode("x" "y")`,
+			shouldError: true,
+			expected:    nil,
+		},
+		"attribute is unchanged": {
+			line:        `policy = jsonencode(`,
+			shouldError: true,
+			expected:    nil,
+		},
+		"not jsonencode": {
+			line:        `+ policy = "jsonencode("`,
+			shouldError: true,
+			expected:    nil,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := NewJSONEncodeAttributeChangeFromLine(tc.line)
+			if err == nil && tc.shouldError {
+				t.Fatalf("Expected an error but didn't get one")
+			}
+
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Fatalf("Expected: %v but got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestIsJSONEncodeAttributeTerminator(t *testing.T) {
+	cases := map[string]struct {
+		line     string
+		expected bool
+	}{
+		"terminator":                    {line: ")", expected: true},
+		"terminator padded with spaces": {line: "    )", expected: true},
+		"deleted":                       {line: ") -> null", expected: true},
+		"computed":                      {line: ") -> (known after apply)", expected: true},
+		"sensitive":                     {line: ") -> (sensitive value)", expected: true},
+		"sensitive short":               {line: ") -> (sensitive)", expected: true},
+		"other line":                    {line: "}", expected: false},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := IsJSONEncodeAttributeTerminator(tc.line); got != tc.expected {
+				t.Fatalf("Expected: %v but got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestIsJSONEncodeValueLine(t *testing.T) {
+	cases := map[string]struct {
+		line     string
+		expected bool
+	}{
+		"empty line": {
+			line:     "",
+			expected: false,
+		},
+		"changed value": {
+			line:     `~ "old" -> "new"`,
+			expected: true,
+		},
+		"number": {
+			line:     `+ 1`,
+			expected: true,
+		},
+		"attribute": {
+			line:     `+ name = "value"`,
+			expected: false,
+		},
+		"terminator": {
+			line:     `)`,
+			expected: false,
+		},
+		"computed": {
+			line:     `~ "old" -> (known after apply)`,
+			expected: true,
+		},
+		"null": {
+			line:     `- null`,
+			expected: true,
+		},
+		"block terminator": {
+			line:     `}`,
+			expected: false,
+		},
+		"array terminator": {
+			line:     `]`,
+			expected: false,
+		},
+		"heredoc terminator": {
+			line:     `EOT`,
+			expected: false,
+		},
+		"bare token after a value": {
+			line:     `~ "old" -> new`,
+			expected: false,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := IsJSONEncodeValueLine(tc.line); got != tc.expected {
+				t.Fatalf("Expected: %v but got %v", tc.expected, got)
+			}
+		})
+	}
+}
@@ -7,62 +7,101 @@ import (
 
 type MapAttributeChange struct {
 	Name             string
-	AttributeChanges []attributeChange
+	AttributeChanges []Attr
 	UpdateType       UpdateType
+
+	// ForcesReplacement indicates whether the change to the attribute forces the resource to be replaced
+	ForcesReplacement bool
+
+	// Kind contains the type of collection
+	// Refer to multiline_attribute.go for possible values
+	Kind CollectionKind
+
+	// Computed indicates whether the whole value after the change is computed during apply
+	// Example: } -> (known after apply)
+	Computed bool
+
+	// BeforeSensitive and AfterSensitive indicate whether the value is sensitive before and after the change
+	// They are set from a warning that the value changes sensitivity
+	BeforeSensitive bool
+	AfterSensitive  bool
+
+	// Hidden contains the number of unchanged values omitted from the plan output
+	Hidden HiddenCount
 }
 
-var _ attributeChange = &MapAttributeChange{}
+var _ Attr = &MapAttributeChange{}
 
 // IsMapAttributeChangeLine returns true if the line is a valid attribute change
 // This requires the line to start with "+", "-" or "~", not be followed with "resource" or "data", and ends with "{".
+// Nested blocks, such as "metadata {", are not map attributes, and are checked with IsBlockChangeLine instead
+// Empty jsonencode values, such as "jsonencode({})", are checked with IsJSONEncodeAttributeChangeLine instead
 func IsMapAttributeChangeLine(line string) bool {
-	line = strings.TrimSpace(line)
+	line, _ = trimForcesReplacement(line)
 	// validPrefix := strings.HasPrefix(line, "+") || strings.HasPrefix(line, "-") || strings.HasPrefix(line, "~")
 	validSuffix := strings.HasSuffix(line, "{") || IsOneLineEmptyMapAttribute(line)
-	return validSuffix && !IsResourceChangeLine(line)
+	return validSuffix && !IsResourceChangeLine(line) && !IsBlockChangeLine(line) && !IsJSONEncodeAttributeChangeLine(line)
 }
 
-// IsMapAttributeTerminator returns true if the line is a "}" or "},"
+// IsMapAttributeTerminator returns true if the line is a "}", "})", "}," or any of them followed by " -> null" or " -> (known after apply)"
 func IsMapAttributeTerminator(line string) bool {
-	return strings.TrimSuffix(strings.TrimSuffix(strings.TrimSpace(line), ","), " -> null") == "}"
+	line = strings.TrimSuffix(strings.TrimSpace(line), ",")
+	line = strings.TrimSuffix(strings.TrimSuffix(line, " -> null"), ATTRIBUTE_CHANGE_DELIMITER+COMPUTED_VALUE)
+	return line == "}" || line == "})"
 }
 
-// IsOneLineEmptyMapAttribute returns true if the line ends with a "{}"
+// IsOneLineEmptyMapAttribute returns true if the line ends with a "{}" or "{})", or with "{}) -> null"
+// A deleted empty map without a wrapper, such as "{} -> null", is read as an attribute with an empty value instead
 func IsOneLineEmptyMapAttribute(line string) bool {
-	return strings.HasSuffix(line, "{}")
+	line, _ = trimForcesReplacement(line)
+	return strings.HasSuffix(line, "{}") || strings.HasSuffix(line, "{})") || strings.HasSuffix(line, "{}) -> null")
 }
 
 // NewMapAttributeChangeFromLine initializes an AttributeChange from a line containing an attribute change
 // It expects a line that passes the IsAttributeChangeLine check
 func NewMapAttributeChangeFromLine(line string) (*MapAttributeChange, error) {
-	line = strings.TrimSpace(line)
+	line, forcesReplacement := trimForcesReplacement(line)
 	if !IsMapAttributeChangeLine(line) {
 		return nil, fmt.Errorf("%s is not a valid line to initialize a MapAttributeChange", line)
 	}
 
 	attributeName := getMultiLineAttributeName(line)
+	kind := getMapCollectionKind(line)
 	if strings.HasPrefix(line, "+") {
 		// add
 		return &MapAttributeChange{
-			Name:       attributeName,
-			UpdateType: NewResource,
+			Name:              attributeName,
+			UpdateType:        NewResource,
+			Kind:              kind,
+			ForcesReplacement: forcesReplacement,
 		}, nil
 	} else if strings.HasPrefix(line, "-") {
 		// destroy
 		return &MapAttributeChange{
-			Name:       attributeName,
-			UpdateType: DestroyResource,
+			Name:              attributeName,
+			UpdateType:        DestroyResource,
+			Kind:              kind,
+			ForcesReplacement: forcesReplacement,
 		}, nil
 	} else if strings.HasPrefix(line, "~") {
 		// replace
+		updateType := UpdateInPlaceResource
+		if forcesReplacement {
+			updateType = ForceReplaceResource
+		}
+
 		return &MapAttributeChange{
-			Name:       attributeName,
-			UpdateType: UpdateInPlaceResource,
+			Name:              attributeName,
+			UpdateType:        updateType,
+			Kind:              kind,
+			ForcesReplacement: forcesReplacement,
 		}, nil
 	} else {
 		return &MapAttributeChange{
-			Name:       attributeName,
-			UpdateType: NoOpResource,
+			Name:              attributeName,
+			UpdateType:        NoOpResource,
+			Kind:              kind,
+			ForcesReplacement: forcesReplacement,
 		}, nil
 	}
 }
@@ -77,8 +116,11 @@ func (m *MapAttributeChange) GetUpdateType() UpdateType {
 	return m.UpdateType
 }
 
-// IsSensitive returns true if the attribute contains a sensitive value
+// IsSensitive returns true if the attribute contains a sensitive value, or is sensitive before or after the change
 func (m *MapAttributeChange) IsSensitive() bool {
+	if m.BeforeSensitive || m.AfterSensitive {
+		return true
+	}
 	for _, ac := range m.AttributeChanges {
 		if ac.IsSensitive() {
 			return true
@@ -87,8 +129,11 @@ func (m *MapAttributeChange) IsSensitive() bool {
 	return false
 }
 
-// IsComputed returns true if the attribute contains a computed value
+// IsComputed returns true if the attribute contains a computed value, or the whole value after the change is computed
 func (m *MapAttributeChange) IsComputed() bool {
+	if m.Computed {
+		return true
+	}
 	for _, ac := range m.AttributeChanges {
 		if ac.IsComputed() {
 			return true
@@ -102,6 +147,11 @@ func (m *MapAttributeChange) IsNoOp() bool {
 	return m.UpdateType == NoOpResource
 }
 
+// GetForcesReplacement returns true if the change to the attribute forces the resource to be replaced
+func (m *MapAttributeChange) GetForcesReplacement() bool {
+	return m.ForcesReplacement
+}
+
 func (m *MapAttributeChange) GetBefore(opts ...GetBeforeAfterOptions) interface{} {
 	result := map[string]interface{}{}
 
@@ -119,6 +169,10 @@ attrs:
 }
 
 func (m *MapAttributeChange) GetAfter(opts ...GetBeforeAfterOptions) interface{} {
+	if m.Computed {
+		return UnknownMarker
+	}
+
 	result := map[string]interface{}{}
 
 attrs:
@@ -133,3 +187,19 @@ attrs:
 
 	return result
 }
+
+// GetBeforeValue returns the initial value of the attribute as a Value
+func (m *MapAttributeChange) GetBeforeValue(opts ...GetBeforeAfterOptions) Value {
+	return ValueOf(m.GetBefore(opts...))
+}
+
+// GetAfterValue returns the planned value of the attribute as a Value
+func (m *MapAttributeChange) GetAfterValue(opts ...GetBeforeAfterOptions) Value {
+	return ValueOf(m.GetAfter(opts...))
+}
+
+// Attribute returns the attribute at the path, relative to the map
+// Refer to ParsePath for the format of the path
+func (m *MapAttributeChange) Attribute(path string) (Attr, error) {
+	return findAttr(m.AttributeChanges, false, path)
+}
@@ -16,28 +16,13 @@ func TestNewMapAttributeChangeFromLine(t *testing.T) {
 			shouldError: true,
 			expected:    nil,
 		},
-		"attribute created": {
-			line:        `+ attribute {`,
-			shouldError: false,
-			expected: &MapAttributeChange{
-				Name:       "attribute",
-				UpdateType: NewResource,
-			},
-		},
 		"attribute created with delimiter": {
 			line:        `+ attribute = {`,
 			shouldError: false,
 			expected: &MapAttributeChange{
 				Name:       "attribute",
 				UpdateType: NewResource,
-			},
-		},
-		"attribute deleted": {
-			line:        `- attribute {`,
-			shouldError: false,
-			expected: &MapAttributeChange{
-				Name:       "attribute",
-				UpdateType: DestroyResource,
+				Kind:       MapCollection,
 			},
 		},
 		"attribute deleted with delimiter": {
@@ -46,14 +31,7 @@ func TestNewMapAttributeChangeFromLine(t *testing.T) {
 			expected: &MapAttributeChange{
 				Name:       "attribute",
 				UpdateType: DestroyResource,
-			},
-		},
-		"attribute changed": {
-			line:        `~ attribute {`,
-			shouldError: false,
-			expected: &MapAttributeChange{
-				Name:       "attribute",
-				UpdateType: UpdateInPlaceResource,
+				Kind:       MapCollection,
 			},
 		},
 		"attribute changed with delimiter": {
@@ -62,14 +40,7 @@ func TestNewMapAttributeChangeFromLine(t *testing.T) {
 			expected: &MapAttributeChange{
 				Name:       "attribute",
 				UpdateType: UpdateInPlaceResource,
-			},
-		},
-		"attribute is unchanged": {
-			line:        `attribute {`,
-			shouldError: false,
-			expected: &MapAttributeChange{
-				Name:       "attribute",
-				UpdateType: NoOpResource,
+				Kind:       MapCollection,
 			},
 		},
 		"attribute with delimiter is unchanged": {
@@ -78,6 +49,7 @@ func TestNewMapAttributeChangeFromLine(t *testing.T) {
 			expected: &MapAttributeChange{
 				Name:       "attribute",
 				UpdateType: NoOpResource,
+				Kind:       MapCollection,
 			},
 		},
 		"unchanged empty map": {
@@ -86,8 +58,14 @@ func TestNewMapAttributeChangeFromLine(t *testing.T) {
 			expected: &MapAttributeChange{
 				Name:       "attribute",
 				UpdateType: NoOpResource,
+				Kind:       MapCollection,
 			},
 		},
+		"nested block": {
+			line:        `+ attribute {`,
+			shouldError: true,
+			expected:    nil,
+		},
 		"resource line": {
 			line:        `+ resource "type" "name" {`,
 			shouldError: true,
@@ -99,6 +77,7 @@ func TestNewMapAttributeChangeFromLine(t *testing.T) {
 			expected: &MapAttributeChange{
 				Name:       "attribute",
 				UpdateType: NewResource,
+				Kind:       MapCollection,
 			},
 		},
 		"one line empty map": {
@@ -107,14 +86,44 @@ func TestNewMapAttributeChangeFromLine(t *testing.T) {
 			expected: &MapAttributeChange{
 				Name:       "attribute",
 				UpdateType: NewResource,
+				Kind:       MapCollection,
 			},
 		},
-		"one line empty map with no delimiter": {
-			line:        `+ attribute {}`,
+		"attribute changed and forces replacement": {
+			line:        `~ attribute = { # forces replacement`,
+			shouldError: false,
+			expected: &MapAttributeChange{
+				Name:              "attribute",
+				UpdateType:        ForceReplaceResource,
+				Kind:              MapCollection,
+				ForcesReplacement: true,
+			},
+		},
+		"wrapped map created": {
+			line:        `+ attribute = tomap({`,
+			shouldError: false,
+			expected: &MapAttributeChange{
+				Name:       "attribute",
+				UpdateType: NewResource,
+				Kind:       MapCollection,
+			},
+		},
+		"one line empty wrapped map": {
+			line:        `+ attribute = tomap({})`,
 			shouldError: false,
 			expected: &MapAttributeChange{
 				Name:       "attribute",
 				UpdateType: NewResource,
+				Kind:       MapCollection,
+			},
+		},
+		"object in an array": {
+			line:        `+ {`,
+			shouldError: false,
+			expected: &MapAttributeChange{
+				Name:       "",
+				UpdateType: NewResource,
+				Kind:       ObjectCollection,
 			},
 		},
 		"other line": {
@@ -138,6 +147,53 @@ func TestNewMapAttributeChangeFromLine(t *testing.T) {
 	}
 }
 
+func TestIsMapAttributeTerminator(t *testing.T) {
+	cases := map[string]struct {
+		line     string
+		expected bool
+	}{
+		"empty line": {
+			line:     "",
+			expected: false,
+		},
+		"map terminator": {
+			line:     "}",
+			expected: true,
+		},
+		"map terminator in an array": {
+			line:     "},",
+			expected: true,
+		},
+		"map terminator deleted": {
+			line:     "} -> null",
+			expected: true,
+		},
+		"map terminator computed": {
+			line:     "} -> (known after apply)",
+			expected: true,
+		},
+		"wrapped map terminator": {
+			line:     "})",
+			expected: true,
+		},
+		"wrapped map terminator deleted": {
+			line:     "}) -> null",
+			expected: true,
+		},
+		"array terminator": {
+			line:     "])",
+			expected: false,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := IsMapAttributeTerminator(tc.line); got != tc.expected {
+				t.Errorf("Expected: %v but got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
 func TestMapGetBefore(t *testing.T) {
 	cases := map[string]struct {
 		ma       *MapAttributeChange
@@ -146,7 +202,7 @@ func TestMapGetBefore(t *testing.T) {
 	}{
 		"one attribute": {
 			ma: &MapAttributeChange{
-				AttributeChanges: []attributeChange{
+				AttributeChanges: []Attr{
 					&AttributeChange{
 						Name:     "attribute",
 						OldValue: "oldValue",
@@ -160,7 +216,7 @@ func TestMapGetBefore(t *testing.T) {
 		},
 		"multiple attribute": {
 			ma: &MapAttributeChange{
-				AttributeChanges: []attributeChange{
+				AttributeChanges: []Attr{
 					&AttributeChange{
 						Name:     "attribute1",
 						OldValue: "oldValue1",
@@ -180,10 +236,10 @@ func TestMapGetBefore(t *testing.T) {
 		},
 		"map attribute": {
 			ma: &MapAttributeChange{
-				AttributeChanges: []attributeChange{
+				AttributeChanges: []Attr{
 					&MapAttributeChange{
 						Name: "map",
-						AttributeChanges: []attributeChange{
+						AttributeChanges: []Attr{
 							&AttributeChange{
 								Name:     "attribute1",
 								OldValue: "oldValue1",
@@ -207,10 +263,10 @@ func TestMapGetBefore(t *testing.T) {
 		},
 		"array attribute": {
 			ma: &MapAttributeChange{
-				AttributeChanges: []attributeChange{
+				AttributeChanges: []Attr{
 					&ArrayAttributeChange{
 						Name: "array",
-						AttributeChanges: []attributeChange{
+						AttributeChanges: []Attr{
 							&AttributeChange{
 								OldValue: "oldValue1",
 								NewValue: "newValue1",
@@ -227,7 +283,7 @@ func TestMapGetBefore(t *testing.T) {
 		},
 		"map and normal attribute": {
 			ma: &MapAttributeChange{
-				AttributeChanges: []attributeChange{
+				AttributeChanges: []Attr{
 					&AttributeChange{
 						Name:     "attribute",
 						OldValue: "oldValue",
@@ -235,7 +291,7 @@ func TestMapGetBefore(t *testing.T) {
 					},
 					&MapAttributeChange{
 						Name: "map",
-						AttributeChanges: []attributeChange{
+						AttributeChanges: []Attr{
 							&AttributeChange{
 								Name:     "attribute1",
 								OldValue: "oldValue1",
@@ -260,11 +316,11 @@ func TestMapGetBefore(t *testing.T) {
 		},
 		"ignore sensitive values": {
 			ma: &MapAttributeChange{
-				AttributeChanges: []attributeChange{
+				AttributeChanges: []Attr{
 					&AttributeChange{
 						Name:     "attribute",
-						OldValue: "(sensitive value)",
-						NewValue: "(sensitive value)",
+						OldValue: SensitiveMarker,
+						NewValue: SensitiveMarker,
 					},
 					&AttributeChange{
 						Name:     "attribute2",
@@ -297,7 +353,7 @@ func TestMapGetAfter(t *testing.T) {
 	}{
 		"one attribute": {
 			ma: &MapAttributeChange{
-				AttributeChanges: []attributeChange{
+				AttributeChanges: []Attr{
 					&AttributeChange{
 						Name:     "attribute",
 						OldValue: "oldValue",
@@ -311,7 +367,7 @@ func TestMapGetAfter(t *testing.T) {
 		},
 		"multiple attribute": {
 			ma: &MapAttributeChange{
-				AttributeChanges: []attributeChange{
+				AttributeChanges: []Attr{
 					&AttributeChange{
 						Name:     "attribute1",
 						OldValue: "oldValue1",
@@ -331,10 +387,10 @@ func TestMapGetAfter(t *testing.T) {
 		},
 		"map attribute": {
 			ma: &MapAttributeChange{
-				AttributeChanges: []attributeChange{
+				AttributeChanges: []Attr{
 					&MapAttributeChange{
 						Name: "map",
-						AttributeChanges: []attributeChange{
+						AttributeChanges: []Attr{
 							&AttributeChange{
 								Name:     "attribute1",
 								OldValue: "oldValue1",
@@ -358,10 +414,10 @@ func TestMapGetAfter(t *testing.T) {
 		},
 		"array attribute": {
 			ma: &MapAttributeChange{
-				AttributeChanges: []attributeChange{
+				AttributeChanges: []Attr{
 					&ArrayAttributeChange{
 						Name: "array",
-						AttributeChanges: []attributeChange{
+						AttributeChanges: []Attr{
 							&AttributeChange{
 								OldValue: "oldValue1",
 								NewValue: "newValue1",
@@ -378,7 +434,7 @@ func TestMapGetAfter(t *testing.T) {
 		},
 		"map and normal attribute": {
 			ma: &MapAttributeChange{
-				AttributeChanges: []attributeChange{
+				AttributeChanges: []Attr{
 					&AttributeChange{
 						Name:     "attribute",
 						OldValue: "oldValue",
@@ -386,7 +442,7 @@ func TestMapGetAfter(t *testing.T) {
 					},
 					&MapAttributeChange{
 						Name: "map",
-						AttributeChanges: []attributeChange{
+						AttributeChanges: []Attr{
 							&AttributeChange{
 								Name:     "attribute1",
 								OldValue: "oldValue1",
@@ -411,11 +467,11 @@ func TestMapGetAfter(t *testing.T) {
 		},
 		"ignore sensitive values": {
 			ma: &MapAttributeChange{
-				AttributeChanges: []attributeChange{
+				AttributeChanges: []Attr{
 					&AttributeChange{
 						Name:     "attribute",
-						OldValue: "(sensitive value)",
-						NewValue: "(sensitive value)",
+						OldValue: SensitiveMarker,
+						NewValue: SensitiveMarker,
 					},
 					&AttributeChange{
 						Name:     "attribute2",
@@ -431,11 +487,11 @@ func TestMapGetAfter(t *testing.T) {
 		},
 		"ignore computed values": {
 			ma: &MapAttributeChange{
-				AttributeChanges: []attributeChange{
+				AttributeChanges: []Attr{
 					&AttributeChange{
 						Name:     "attribute",
 						OldValue: "oldValue",
-						NewValue: "(known after apply)",
+						NewValue: UnknownMarker,
 					},
 					&AttributeChange{
 						Name:     "attribute2",
@@ -451,11 +507,11 @@ func TestMapGetAfter(t *testing.T) {
 		},
 		"computed only": {
 			ma: &MapAttributeChange{
-				AttributeChanges: []attributeChange{
+				AttributeChanges: []Attr{
 					&AttributeChange{
 						Name:     "attribute",
 						OldValue: "oldValue",
-						NewValue: "(known after apply)",
+						NewValue: UnknownMarker,
 					},
 					&AttributeChange{
 						Name:     "attribute2",
@@ -465,7 +521,7 @@ func TestMapGetAfter(t *testing.T) {
 				},
 			},
 			expected: map[string]interface{}{
-				"attribute": "(known after apply)",
+				"attribute": UnknownMarker,
 			},
 			opts: []GetBeforeAfterOptions{ComputedOnly},
 		},
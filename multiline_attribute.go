@@ -6,6 +6,46 @@ import (
 
 // TODO: interface
 
+// CollectionKind is the type of collection an array or map attribute holds
+type CollectionKind string
+
+const (
+	ListCollection CollectionKind = "list"
+	// SetCollection elements are unordered, so the order in the plan output is not meaningful
+	SetCollection CollectionKind = "set"
+	// TupleCollection is the kind of JSON arrays in jsonencode values
+	TupleCollection CollectionKind = "tuple"
+	MapCollection   CollectionKind = "map"
+	// ObjectCollection is the kind of objects in arrays and JSON objects in jsonencode values
+	ObjectCollection CollectionKind = "object"
+)
+
+const (
+	// terraform v0.15+ wraps collections with a conversion function when the type is not obvious from the value
+	TOLIST_WRAPPER = "tolist("
+	TOSET_WRAPPER  = "toset("
+	TOMAP_WRAPPER  = "tomap("
+)
+
+// getArrayCollectionKind returns the kind of collection from a line starting an array attribute
+// Example: + tags = toset([ -> SetCollection
+func getArrayCollectionKind(line string) CollectionKind {
+	if strings.Contains(line, TOSET_WRAPPER+"[") {
+		return SetCollection
+	}
+	return ListCollection
+}
+
+// getMapCollectionKind returns the kind of collection from a line starting a map attribute
+// Named attributes are maps, and unnamed values are objects
+// Example: + tags = tomap({ -> MapCollection
+func getMapCollectionKind(line string) CollectionKind {
+	if strings.Contains(line, TOMAP_WRAPPER+"{") || strings.Contains(removeChangeTypeCharacters(line), ATTRIBUTE_DEFINITON_DELIMITER) {
+		return MapCollection
+	}
+	return ObjectCollection
+}
+
 func getMultiLineAttributeName(line string) string {
 	line = removeChangeTypeCharacters(line)
 	// Multiline attributes may or may not have a name
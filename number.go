@@ -0,0 +1,63 @@
+package tfplanparse
+
+import (
+	"encoding/json"
+	"math/big"
+	"regexp"
+	"strconv"
+)
+
+type NumberMode int
+
+const (
+	// NativeNumbers converts numbers to an int, or to a float64 if they are not integers or do not fit in an int
+	NativeNumbers NumberMode = iota
+	// ExactNumbers keeps numbers as a Number containing the literal from the plan output
+	ExactNumbers
+)
+
+// numberLiteralRegexp matches the same numbers as JSON, so every Number can be encoded as a JSON number
+// Terraform never renders numbers with leading zeros, so tokens such as 0123 are kept as strings
+var numberLiteralRegexp = regexp.MustCompile(`^-?(0|[1-9][0-9]*)(\.[0-9]+)?([eE][+-]?[0-9]+)?$`)
+
+// Number is a number kept as the literal from the plan output, so that no precision is lost
+// Example: 123456789012345678901234567890 stays "123456789012345678901234567890", and 1e3 stays "1e3"
+type Number string
+
+// String returns the literal of the number
+func (n Number) String() string {
+	return string(n)
+}
+
+// Int64 returns the number as an int64
+// It returns an error if the number is not an integer or does not fit in an int64
+func (n Number) Int64() (int64, error) {
+	return strconv.ParseInt(string(n), 10, 64)
+}
+
+// Float64 returns the number as a float64, which may lose precision
+func (n Number) Float64() (float64, error) {
+	return strconv.ParseFloat(string(n), 64)
+}
+
+// BigFloat returns the number as a big.Float
+// The precision is large enough to represent every digit of the literal
+func (n Number) BigFloat() (*big.Float, error) {
+	prec := uint(len(n)) * 4
+	if prec < 64 {
+		prec = 64
+	}
+
+	f, _, err := big.ParseFloat(string(n), 10, prec, big.ToNearestEven)
+	return f, err
+}
+
+// MarshalJSON encodes the number as a JSON number with every digit of the literal
+func (n Number) MarshalJSON() ([]byte, error) {
+	return json.Marshal(json.Number(n))
+}
+
+// isNumberLiteral returns true if the token is a decimal number in the format terraform renders numbers
+func isNumberLiteral(token string) bool {
+	return numberLiteralRegexp.MatchString(token)
+}
@@ -0,0 +1,120 @@
+package tfplanparse
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+)
+
+func TestNumber(t *testing.T) {
+	cases := map[string]struct {
+		number           Number
+		expectedInt64    int64
+		int64ShouldError bool
+		expectedFloat64  float64
+		expectedBigFloat string
+	}{
+		"int": {
+			number:           Number("42"),
+			expectedInt64:    42,
+			expectedFloat64:  42,
+			expectedBigFloat: "42",
+		},
+		"float with many digits": {
+			number:           Number("12345678901234567890.5"),
+			int64ShouldError: true,
+			expectedFloat64:  1.2345678901234567e19,
+			expectedBigFloat: "12345678901234567890.5",
+		},
+		"exponent": {
+			number:           Number("1e3"),
+			int64ShouldError: true,
+			expectedFloat64:  1000,
+			expectedBigFloat: "1000",
+		},
+		"larger than an int64": {
+			number:           Number("123456789012345678901234567890"),
+			int64ShouldError: true,
+			expectedFloat64:  1.2345678901234568e29,
+			expectedBigFloat: "123456789012345678901234567890",
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			i, err := tc.number.Int64()
+			if err == nil && tc.int64ShouldError {
+				t.Errorf("Expected an error but didn't get one")
+			}
+			if err != nil && !tc.int64ShouldError {
+				t.Errorf("Unexpected error: %s", err)
+			}
+			if !tc.int64ShouldError && i != tc.expectedInt64 {
+				t.Errorf("Expected int64: %v but got %v", tc.expectedInt64, i)
+			}
+
+			f, err := tc.number.Float64()
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if f != tc.expectedFloat64 {
+				t.Errorf("Expected float64: %v but got %v", tc.expectedFloat64, f)
+			}
+
+			bf, err := tc.number.BigFloat()
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if got := bf.Text('f', -1); got != tc.expectedBigFloat {
+				t.Errorf("Expected big.Float: %v but got %v", tc.expectedBigFloat, got)
+			}
+			if bf.Acc() != big.Exact {
+				t.Errorf("Expected an exact big.Float but got %v", bf.Acc())
+			}
+		})
+	}
+}
+
+func TestNumberMarshalJSON(t *testing.T) {
+	got, err := json.Marshal(map[string]interface{}{"n": Number("123456789012345678901234567890")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := `{"n":123456789012345678901234567890}`; string(got) != expected {
+		t.Errorf("Expected: %s but got %s", expected, got)
+	}
+}
+
+func TestIsNumberLiteral(t *testing.T) {
+	cases := map[string]struct {
+		token    string
+		expected bool
+	}{
+		"int":           {token: "42", expected: true},
+		"zero":          {token: "0", expected: true},
+		"negative":      {token: "-1", expected: true},
+		"float":         {token: "0.5", expected: true},
+		"exponent":      {token: "1e3", expected: true},
+		"leading zeros": {token: "012345678901", expected: false},
+		"hex":           {token: "0x1F", expected: false},
+		"text":          {token: "Inf", expected: false},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := isNumberLiteral(tc.token); got != tc.expected {
+				t.Fatalf("Expected: %v but got %v", tc.expected, got)
+			}
+			if !tc.expected {
+				return
+			}
+
+			// every literal read as a Number can be encoded as JSON
+			got, err := json.Marshal(Number(tc.token))
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if string(got) != tc.token {
+				t.Errorf("Expected: %s but got %s", tc.token, got)
+			}
+		})
+	}
+}
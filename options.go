@@ -1,19 +1,81 @@
 package tfplanparse
 
-type GetBeforeAfterOptions func(a attributeChange) bool
+type GetBeforeAfterOptions func(a Attr) bool
 
-func IgnoreComputed(a attributeChange) bool {
+func IgnoreComputed(a Attr) bool {
 	return a.IsComputed()
 }
 
-func IgnoreSensitive(a attributeChange) bool {
+func IgnoreSensitive(a Attr) bool {
 	return a.IsSensitive()
 }
 
-func IgnoreNoOp(a attributeChange) bool {
+func IgnoreNoOp(a Attr) bool {
 	return a.IsNoOp()
 }
 
-func ComputedOnly(a attributeChange) bool {
+func ComputedOnly(a Attr) bool {
 	return !a.IsComputed()
 }
+
+// ResourceChangeFilter returns true if the resource change should be removed by FilterResourceChanges
+type ResourceChangeFilter func(rc *ResourceChange) bool
+
+// FilterResourceChanges returns the resource changes that are not removed by any of the filters
+func FilterResourceChanges(rcs []*ResourceChange, filters ...ResourceChangeFilter) []*ResourceChange {
+	result := []*ResourceChange{}
+
+changes:
+	for _, rc := range rcs {
+		for _, filter := range filters {
+			if filter(rc) {
+				continue changes
+			}
+		}
+		result = append(result, rc)
+	}
+
+	return result
+}
+
+func IgnoreDataSources(rc *ResourceChange) bool {
+	return rc.IsDataSource()
+}
+
+func DataSourcesOnly(rc *ResourceChange) bool {
+	return !rc.IsDataSource()
+}
+
+func IgnoreDeposed(rc *ResourceChange) bool {
+	return rc.IsDeposed()
+}
+
+func DeposedOnly(rc *ResourceChange) bool {
+	return !rc.IsDeposed()
+}
+
+// ParseOption configures how the plan output is parsed
+type ParseOption func(c *parseConfig)
+
+type parseConfig struct {
+	numberMode NumberMode
+}
+
+func newParseConfig(opts ...ParseOption) *parseConfig {
+	c := &parseConfig{
+		numberMode: NativeNumbers,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	return c
+}
+
+// WithNumberMode sets how numbers in attribute values are converted
+// Use ExactNumbers to keep numbers as a Number without losing precision
+func WithNumberMode(mode NumberMode) ParseOption {
+	return func(c *parseConfig) {
+		c.numberMode = mode
+	}
+}
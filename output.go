@@ -0,0 +1,106 @@
+package tfplanparse
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	OUTPUTS_START_STRING = "Changes to Outputs:"
+)
+
+type OutputChange struct {
+	// Name contains the name of the output
+	Name string
+
+	// UpdateType contains the type of update
+	// Refer to updatetype.go for possible values
+	UpdateType UpdateType
+
+	// Change contains the parsed value of the output
+	Change Attr
+}
+
+var _ Attr = &OutputChange{}
+
+// IsOutputsStartLine returns true if the line is the header preceding the planned output changes
+func IsOutputsStartLine(line string) bool {
+	return strings.TrimSpace(line) == OUTPUTS_START_STRING
+}
+
+// IsOutputChangeLine returns true if the line is a valid output change
+// This requires the line to start with "+", "-" or "~", and be a valid attribute of any type
+func IsOutputChangeLine(line string) bool {
+	line = strings.TrimSpace(line)
+	validPrefix := strings.HasPrefix(line, "+ ") || strings.HasPrefix(line, "- ") || strings.HasPrefix(line, "~ ")
+	validAttribute := IsMapAttributeChangeLine(line) ||
+		IsArrayAttributeChangeLine(line) ||
+		IsJSONEncodeAttributeChangeLine(line) ||
+		IsHeredocAttributeChangeLine(line) ||
+		IsAttributeChangeLine(line)
+
+	return validPrefix && validAttribute
+}
+
+// NewOutputChange creates an OutputChange from the parsed value of the output
+func NewOutputChange(ac Attr) (*OutputChange, error) {
+	if ac == nil {
+		return nil, fmt.Errorf("cannot initialize an OutputChange without a value")
+	}
+
+	return &OutputChange{
+		Name:       ac.GetName(),
+		UpdateType: ac.GetUpdateType(),
+		Change:     ac,
+	}, nil
+}
+
+// GetName returns the name of the output
+func (o *OutputChange) GetName() string {
+	return o.Name
+}
+
+// GetUpdateType returns the UpdateType of the output
+func (o *OutputChange) GetUpdateType() UpdateType {
+	return o.UpdateType
+}
+
+// GetBefore returns the initial value of the output
+func (o *OutputChange) GetBefore(opts ...GetBeforeAfterOptions) interface{} {
+	return o.Change.GetBefore(opts...)
+}
+
+// GetAfter returns the planned value of the output
+func (o *OutputChange) GetAfter(opts ...GetBeforeAfterOptions) interface{} {
+	return o.Change.GetAfter(opts...)
+}
+
+// IsSensitive returns true if the output contains a sensitive value
+func (o *OutputChange) IsSensitive() bool {
+	return o.Change.IsSensitive()
+}
+
+// IsComputed returns true if the output contains a computed value
+func (o *OutputChange) IsComputed() bool {
+	return o.Change.IsComputed()
+}
+
+// GetForcesReplacement returns true if the output value is marked as forcing a replacement
+func (o *OutputChange) GetForcesReplacement() bool {
+	return o.Change.GetForcesReplacement()
+}
+
+// IsNoOp returns true if the output has not changed
+func (o *OutputChange) IsNoOp() bool {
+	return o.UpdateType == NoOpResource
+}
+
+// GetBeforeValue returns the initial value of the output as a Value
+func (o *OutputChange) GetBeforeValue(opts ...GetBeforeAfterOptions) Value {
+	return ValueOf(o.GetBefore(opts...))
+}
+
+// GetAfterValue returns the planned value of the output as a Value
+func (o *OutputChange) GetAfterValue(opts ...GetBeforeAfterOptions) Value {
+	return ValueOf(o.GetAfter(opts...))
+}
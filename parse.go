@@ -9,88 +9,251 @@ import (
 )
 
 const (
-	NO_CHANGES_STRING    = "No changes. Infrastructure is up-to-date."
-	CHANGES_START_STRING = "Terraform will perform the following actions:"
-	CHANGES_END_STRING   = "Plan: "
-	ERROR_STRING         = "Error: "
+	NO_CHANGES_STRING       = "No changes. Infrastructure is up-to-date."
+	NO_CHANGES_STRING_V1    = "No changes. Your infrastructure matches the configuration."
+	NO_CHANGES_DRIFT_STRING = "No changes. Your infrastructure still matches the configuration."
+	// The drift note ends with either "Terraform" or "OpenTofu"
+	DRIFT_START_STRING            = "Note: Objects have changed outside of "
+	CHANGES_START_STRING          = "Terraform will perform the following actions:"
+	OPENTOFU_CHANGES_START_STRING = "OpenTofu will perform the following actions:"
+	CHANGES_END_STRING            = "Plan: "
+	ERROR_STRING                  = "Error: "
 )
 
-func Parse(input io.Reader) ([]*ResourceChange, error) {
-	result := []*ResourceChange{}
-	parse := false
+// Parse parses the output of terraform plan and returns the planned resource changes
+func Parse(input io.Reader, opts ...ParseOption) ([]*ResourceChange, error) {
+	plan, err := ParsePlan(input, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return plan.ResourceChanges, nil
+}
+
+func ParseFromFile(filepath string, opts ...ParseOption) ([]*ResourceChange, error) {
+	f, err := os.Open(filepath)
+	if err != nil {
+		return []*ResourceChange{}, err
+	}
+	defer f.Close()
+
+	return Parse(f, opts...)
+}
+
+// planSection is the section of the plan output currently being parsed
+type planSection int
+
+const (
+	noSection planSection = iota
+	driftSection
+	actionsSection
+	outputsSection
+)
+
+// ParsePlan parses the output of terraform plan into a Plan
+func ParsePlan(input io.Reader, opts ...ParseOption) (*Plan, error) {
+	cfg := newParseConfig(opts...)
+	plan := &Plan{
+		Dialect:         UnknownDialect,
+		ResourceChanges: []*ResourceChange{},
+		DriftChanges:    []*ResourceChange{},
+		OutputChanges:   []*OutputChange{},
+		Diagnostics:     []*Diagnostic{},
+	}
+	section := noSection
+	complete := false
+	rescan := false
 	scanner := bufio.NewScanner(input)
 
-	for scanner.Scan() {
+	for rescan || scanner.Scan() {
+		rescan = false
 		text := formatInput(scanner.Bytes())
 		if text == "" {
 			continue
 		}
 
-		if !parse {
-			if strings.Contains(text, NO_CHANGES_STRING) || strings.Contains(text, ERROR_STRING) {
-				// Nothing to parse, return empty plan
-				return result, nil
-			} else if strings.Contains(text, CHANGES_START_STRING) {
-				// Parse all lines from here on
-				parse = true
+		// once opentofu is detected, lines shared with terraform should not override it
+		if dialect := detectDialect(text); dialect != UnknownDialect && plan.Dialect != OpenTofuDialect {
+			plan.Dialect = dialect
+		}
+
+		if IsDiagnosticStartLine(text) {
+			d, unconsumed, err := parseDiagnostic(scanner)
+			if err != nil {
+				return nil, err
 			}
 
+			plan.Diagnostics = append(plan.Diagnostics, d)
+			rescan = unconsumed
+			continue
+		} else if IsDriftStartLine(text) {
+			section = driftSection
+			continue
+		} else if IsChangesStartLine(text) {
+			// Parse all resources from here on
+			section = actionsSection
+			continue
+		} else if IsOutputsStartLine(text) {
+			// Plans that only change outputs do not have a summary, so the outputs complete the plan
+			section = outputsSection
+			complete = true
 			continue
 		}
 
-		if IsResourceCommentLine(text) {
-			rc, err := parseResource(scanner)
+		switch section {
+		case noSection:
+			if IsNoChangesLine(text) {
+				// Nothing to parse, but keep reading in case the rest of the output identifies the dialect
+				complete = true
+			}
+		case driftSection, actionsSection:
+			if IsResourceCommentLine(text) {
+				rc, err := parseResource(scanner, cfg)
+				if err != nil {
+					return nil, err
+				}
+
+				if rc.IsDrift() {
+					plan.DriftChanges = append(plan.DriftChanges, rc)
+				} else {
+					plan.ResourceChanges = append(plan.ResourceChanges, rc)
+				}
+			} else if isUnknownResourceCommentLine(text) {
+				// skipping an unknown change would silently drop the resource, so it is an error instead
+				next, ok := scanPastAnnotations(scanner)
+				if ok && IsResourceChangeLine(next) {
+					return nil, fmt.Errorf("unknown comment line %s", text)
+				}
+				rescan = ok
+			} else if IsNoChangesLine(text) {
+				// drift can be reported for plans with no changes
+				section = noSection
+				complete = true
+			} else if IsPlanSummaryLine(text) {
+				summary, err := NewPlanSummaryFromLine(text)
+				if err != nil {
+					return nil, err
+				}
+
+				// output changes may follow the summary
+				plan.Summary = summary
+				section = noSection
+				complete = true
+			}
+		case outputsSection:
+			if !IsOutputChangeLine(text) {
+				section = noSection
+				continue
+			}
+
+			oc, err := parseOutputChange(scanner, cfg)
 			if err != nil {
 				return nil, err
 			}
 
-			result = append(result, rc)
+			plan.OutputChanges = append(plan.OutputChanges, oc)
 		}
+	}
 
-		if strings.Contains(formatInput(scanner.Bytes()), CHANGES_END_STRING) {
-			// we are done
-			return result, nil
-		}
+	if errors := plan.Errors(); len(errors) > 0 {
+		return plan, &PlanError{Diagnostics: errors}
+	}
+
+	if complete {
+		return plan, nil
 	}
 
 	return nil, fmt.Errorf("unexpected end of input while parsing plan")
 }
 
-func ParseFromFile(filepath string) ([]*ResourceChange, error) {
+func ParsePlanFromFile(filepath string, opts ...ParseOption) (*Plan, error) {
 	f, err := os.Open(filepath)
 	if err != nil {
-		return []*ResourceChange{}, err
+		return nil, err
 	}
+	defer f.Close()
 
-	return Parse(f)
+	return ParsePlan(f, opts...)
 }
 
-func parseResource(s *bufio.Scanner) (*ResourceChange, error) {
+// IsNoChangesLine returns true if the line is the message terraform prints when there are no changes
+func IsNoChangesLine(line string) bool {
+	return strings.Contains(line, NO_CHANGES_STRING) ||
+		strings.Contains(line, NO_CHANGES_STRING_V1) ||
+		strings.Contains(line, NO_CHANGES_DRIFT_STRING)
+}
+
+// IsDriftStartLine returns true if the line is the header preceding the changes made outside of terraform
+func IsDriftStartLine(line string) bool {
+	return strings.HasPrefix(strings.TrimSpace(line), DRIFT_START_STRING)
+}
+
+// IsChangesStartLine returns true if the line is the header preceding the planned resource changes
+func IsChangesStartLine(line string) bool {
+	return strings.Contains(line, CHANGES_START_STRING) || strings.Contains(line, OPENTOFU_CHANGES_START_STRING)
+}
+
+// scanPastAnnotations scans to the next line that is not a resource annotation line
+// The returned bool is false if the end of the input is reached
+func scanPastAnnotations(s *bufio.Scanner) (string, bool) {
+	for s.Scan() {
+		text := formatInput(s.Bytes())
+		if !IsResourceAnnotationLine(text) {
+			return text, true
+		}
+	}
+
+	return "", false
+}
+
+func parseResource(s *bufio.Scanner, cfg *parseConfig) (*ResourceChange, error) {
 	rc, err := NewResourceChangeFromComment(formatInput(s.Bytes()))
 	if err != nil {
 		return nil, err
 	}
+	warning := noSensitivityWarning
 	for s.Scan() {
 		text := formatInput(s.Bytes())
+		n := len(rc.AttributeChanges)
 		switch {
+		case IsSensitivityWarningLine(text):
+			warning = newSensitivityWarningFromLine(text)
 		case IsResourceTerminator(text):
 			return rc, nil
+		case IsResourceChangeLine(text):
+			if err := rc.parseResourceChangeLine(text); err != nil {
+				return nil, err
+			}
+		case IsResourceAnnotationLine(text):
+			if err := rc.parseAnnotationLine(text); err != nil {
+				return nil, err
+			}
+		case IsUnchangedHiddenLine(text):
+			if err := rc.Hidden.addFromLine(text); err != nil {
+				return nil, err
+			}
 		case IsResourceCommentLine(text), strings.Contains(text, CHANGES_END_STRING):
 			return nil, fmt.Errorf("unexpected line while parsing resource attribute: %s", text)
+		case IsBlockChangeLine(text):
+			b, err := parseBlock(s, cfg)
+			if err != nil {
+				return nil, err
+			}
+			rc.AttributeChanges = append(rc.AttributeChanges, b)
 		case IsMapAttributeChangeLine(text):
-			ma, err := parseMapAttribute(s)
+			ma, err := parseMapAttribute(s, cfg)
 			if err != nil {
 				return nil, err
 			}
 			rc.AttributeChanges = append(rc.AttributeChanges, ma)
 		case IsArrayAttributeChangeLine(text):
-			aa, err := parseArrayAttribute(s)
+			aa, err := parseArrayAttribute(s, cfg)
 			if err != nil {
 				return nil, err
 			}
 			rc.AttributeChanges = append(rc.AttributeChanges, aa)
 		case IsJSONEncodeAttributeChangeLine(text):
-			ja, err := parseJSONEncodeAttribute(s)
+			ja, err := parseJSONEncodeAttribute(s, cfg)
 			if err != nil {
 				return nil, err
 			}
@@ -102,18 +265,62 @@ func parseResource(s *bufio.Scanner) (*ResourceChange, error) {
 			}
 			rc.AttributeChanges = append(rc.AttributeChanges, ha)
 		case IsAttributeChangeLine(text):
-			ac, err := NewAttributeChangeFromLine(text)
+			ac, err := newAttributeChangeFromLine(text, cfg)
 			if err != nil {
 				return nil, err
 			}
 			rc.AttributeChanges = append(rc.AttributeChanges, ac)
 		}
+
+		// the warning applies to the attribute or block following it
+		if len(rc.AttributeChanges) > n {
+			warning.apply(rc.AttributeChanges[n])
+			warning = noSensitivityWarning
+		}
 	}
 
 	return nil, fmt.Errorf("unexpected end of input while parsing resource")
 }
 
-func parseMapAttribute(s *bufio.Scanner) (*MapAttributeChange, error) {
+func parseOutputChange(s *bufio.Scanner, cfg *parseConfig) (*OutputChange, error) {
+	text := formatInput(s.Bytes())
+	switch {
+	case IsMapAttributeChangeLine(text):
+		ma, err := parseMapAttribute(s, cfg)
+		if err != nil {
+			return nil, err
+		}
+		return NewOutputChange(ma)
+	case IsArrayAttributeChangeLine(text):
+		aa, err := parseArrayAttribute(s, cfg)
+		if err != nil {
+			return nil, err
+		}
+		return NewOutputChange(aa)
+	case IsJSONEncodeAttributeChangeLine(text):
+		ja, err := parseJSONEncodeAttribute(s, cfg)
+		if err != nil {
+			return nil, err
+		}
+		return NewOutputChange(ja)
+	case IsHeredocAttributeChangeLine(text):
+		ha, err := parseHeredocAttribute(s)
+		if err != nil {
+			return nil, err
+		}
+		return NewOutputChange(ha)
+	case IsAttributeChangeLine(text):
+		ac, err := newAttributeChangeFromLine(text, cfg)
+		if err != nil {
+			return nil, err
+		}
+		return NewOutputChange(ac)
+	}
+
+	return nil, fmt.Errorf("unexpected line while parsing output: %s", text)
+}
+
+func parseMapAttribute(s *bufio.Scanner, cfg *parseConfig) (*MapAttributeChange, error) {
 	normalized := formatInput(s.Bytes())
 	result, err := NewMapAttributeChangeFromLine(normalized)
 	if err != nil {
@@ -123,27 +330,42 @@ func parseMapAttribute(s *bufio.Scanner) (*MapAttributeChange, error) {
 		return result, nil
 	}
 
+	warning := noSensitivityWarning
 	for s.Scan() {
 		text := formatInput(s.Bytes())
+		n := len(result.AttributeChanges)
 		switch {
+		case IsSensitivityWarningLine(text):
+			warning = newSensitivityWarningFromLine(text)
 		case IsMapAttributeTerminator(text):
+			result.Computed = isComputedTerminator(text)
 			return result, nil
+		case IsUnchangedHiddenLine(text):
+			if err := result.Hidden.addFromLine(text); err != nil {
+				return nil, err
+			}
 		case IsResourceCommentLine(text), strings.Contains(text, CHANGES_END_STRING):
 			return nil, fmt.Errorf("unexpected line while parsing map attribute: %s", text)
+		case IsBlockChangeLine(text):
+			b, err := parseBlock(s, cfg)
+			if err != nil {
+				return nil, err
+			}
+			result.AttributeChanges = append(result.AttributeChanges, b)
 		case IsMapAttributeChangeLine(text):
-			ma, err := parseMapAttribute(s)
+			ma, err := parseMapAttribute(s, cfg)
 			if err != nil {
 				return nil, err
 			}
 			result.AttributeChanges = append(result.AttributeChanges, ma)
 		case IsArrayAttributeChangeLine(text):
-			aa, err := parseArrayAttribute(s)
+			aa, err := parseArrayAttribute(s, cfg)
 			if err != nil {
 				return nil, err
 			}
 			result.AttributeChanges = append(result.AttributeChanges, aa)
 		case IsJSONEncodeAttributeChangeLine(text):
-			ja, err := parseJSONEncodeAttribute(s)
+			ja, err := parseJSONEncodeAttribute(s, cfg)
 			if err != nil {
 				return nil, err
 			}
@@ -155,18 +377,101 @@ func parseMapAttribute(s *bufio.Scanner) (*MapAttributeChange, error) {
 			}
 			result.AttributeChanges = append(result.AttributeChanges, ha)
 		case IsAttributeChangeLine(text):
-			ac, err := NewAttributeChangeFromLine(text)
+			ac, err := newAttributeChangeFromLine(text, cfg)
 			if err != nil {
 				return nil, err
 			}
 			result.AttributeChanges = append(result.AttributeChanges, ac)
 		}
+
+		// the warning applies to the attribute or block following it
+		if len(result.AttributeChanges) > n {
+			warning.apply(result.AttributeChanges[n])
+			warning = noSensitivityWarning
+		}
 	}
 
 	return nil, fmt.Errorf("unexpected end of input while parsing map attribute")
 }
 
-func parseArrayAttribute(s *bufio.Scanner) (*ArrayAttributeChange, error) {
+func parseBlock(s *bufio.Scanner, cfg *parseConfig) (*BlockChange, error) {
+	normalized := formatInput(s.Bytes())
+	result, err := NewBlockChangeFromLine(normalized)
+	if err != nil {
+		return nil, err
+	}
+	if IsOneLineEmptyBlock(normalized) {
+		return result, nil
+	}
+
+	warning := noSensitivityWarning
+	for s.Scan() {
+		text := formatInput(s.Bytes())
+		n := len(result.AttributeChanges)
+		switch {
+		case IsSensitivityWarningLine(text):
+			warning = newSensitivityWarningFromLine(text)
+		case IsBlockTerminator(text):
+			return result, nil
+		case IsSensitiveBlockLine(text):
+			result.Sensitive = true
+			result.BeforeSensitive = result.UpdateType != NewResource
+			result.AfterSensitive = result.UpdateType != DestroyResource
+		case IsUnchangedHiddenLine(text):
+			if err := result.Hidden.addFromLine(text); err != nil {
+				return nil, err
+			}
+		case IsResourceCommentLine(text), strings.Contains(text, CHANGES_END_STRING):
+			return nil, fmt.Errorf("unexpected line while parsing block: %s", text)
+		case IsBlockChangeLine(text):
+			b, err := parseBlock(s, cfg)
+			if err != nil {
+				return nil, err
+			}
+			result.AttributeChanges = append(result.AttributeChanges, b)
+		case IsMapAttributeChangeLine(text):
+			ma, err := parseMapAttribute(s, cfg)
+			if err != nil {
+				return nil, err
+			}
+			result.AttributeChanges = append(result.AttributeChanges, ma)
+		case IsArrayAttributeChangeLine(text):
+			aa, err := parseArrayAttribute(s, cfg)
+			if err != nil {
+				return nil, err
+			}
+			result.AttributeChanges = append(result.AttributeChanges, aa)
+		case IsJSONEncodeAttributeChangeLine(text):
+			ja, err := parseJSONEncodeAttribute(s, cfg)
+			if err != nil {
+				return nil, err
+			}
+			result.AttributeChanges = append(result.AttributeChanges, ja)
+		case IsHeredocAttributeChangeLine(text):
+			ha, err := parseHeredocAttribute(s)
+			if err != nil {
+				return nil, err
+			}
+			result.AttributeChanges = append(result.AttributeChanges, ha)
+		case IsAttributeChangeLine(text):
+			ac, err := newAttributeChangeFromLine(text, cfg)
+			if err != nil {
+				return nil, err
+			}
+			result.AttributeChanges = append(result.AttributeChanges, ac)
+		}
+
+		// the warning applies to the attribute or block following it
+		if len(result.AttributeChanges) > n {
+			warning.apply(result.AttributeChanges[n])
+			warning = noSensitivityWarning
+		}
+	}
+
+	return nil, fmt.Errorf("unexpected end of input while parsing block")
+}
+
+func parseArrayAttribute(s *bufio.Scanner, cfg *parseConfig) (*ArrayAttributeChange, error) {
 	normalized := formatInput(s.Bytes())
 	result, err := NewArrayAttributeChangeFromLine(normalized)
 	if err != nil {
@@ -176,27 +481,42 @@ func parseArrayAttribute(s *bufio.Scanner) (*ArrayAttributeChange, error) {
 		return result, nil
 	}
 	// TODO: all elements of array attributes are the same type
+	warning := noSensitivityWarning
 	for s.Scan() {
 		text := formatInput(s.Bytes())
+		n := len(result.AttributeChanges)
 		switch {
+		case IsSensitivityWarningLine(text):
+			warning = newSensitivityWarningFromLine(text)
 		case IsArrayAttributeTerminator(text):
+			result.Computed = isComputedTerminator(text)
 			return result, nil
+		case IsUnchangedHiddenLine(text):
+			if err := result.Hidden.addFromLine(text); err != nil {
+				return nil, err
+			}
 		case IsResourceCommentLine(text), strings.Contains(text, CHANGES_END_STRING):
 			return nil, fmt.Errorf("unexpected line while parsing array attribute: %s", text)
+		case IsBlockChangeLine(text):
+			b, err := parseBlock(s, cfg)
+			if err != nil {
+				return nil, err
+			}
+			result.AttributeChanges = append(result.AttributeChanges, b)
 		case IsMapAttributeChangeLine(text):
-			ma, err := parseMapAttribute(s)
+			ma, err := parseMapAttribute(s, cfg)
 			if err != nil {
 				return nil, err
 			}
 			result.AttributeChanges = append(result.AttributeChanges, ma)
 		case IsArrayAttributeChangeLine(text):
-			ma, err := parseArrayAttribute(s)
+			ma, err := parseArrayAttribute(s, cfg)
 			if err != nil {
 				return nil, err
 			}
 			result.AttributeChanges = append(result.AttributeChanges, ma)
 		case IsJSONEncodeAttributeChangeLine(text):
-			ja, err := parseJSONEncodeAttribute(s)
+			ja, err := parseJSONEncodeAttribute(s, cfg)
 			if err != nil {
 				return nil, err
 			}
@@ -208,43 +528,60 @@ func parseArrayAttribute(s *bufio.Scanner) (*ArrayAttributeChange, error) {
 			}
 			result.AttributeChanges = append(result.AttributeChanges, ha)
 		case IsAttributeChangeArrayItem(text):
-			ac, err := NewAttributeChangeFromArray(text)
+			ac, err := newAttributeChangeFromArray(text, cfg)
 			if err != nil {
 				return nil, err
 			}
 			result.AttributeChanges = append(result.AttributeChanges, ac)
 		}
+
+		// the warning applies to the element following it
+		if len(result.AttributeChanges) > n {
+			warning.apply(result.AttributeChanges[n])
+			warning = noSensitivityWarning
+		}
 	}
 
 	return nil, fmt.Errorf("unexpected end of input while parsing array attribute")
 }
 
-func parseJSONEncodeAttribute(s *bufio.Scanner) (*JSONEncodeAttributeChange, error) {
+func parseJSONEncodeAttribute(s *bufio.Scanner, cfg *parseConfig) (*JSONEncodeAttributeChange, error) {
 	normalized := formatInput(s.Bytes())
-	result, err := NewJSONEncodeAttributeChangeFromLine(normalized)
+	result, err := newJSONEncodeAttributeChangeFromLine(normalized, cfg)
 	if err != nil {
 		return nil, err
 	}
-	// TODO: check if oneline check needed
+	if IsOneLineJSONEncodeAttribute(normalized) {
+		return result, nil
+	}
 
 	for s.Scan() {
 		text := formatInput(s.Bytes())
 		switch {
 		case IsJSONEncodeAttributeTerminator(text):
+			result.Computed = isComputedTerminator(text)
+			result.Sensitive = isSensitiveTerminator(text)
 			return result, nil
-		case IsResourceCommentLine(text), strings.Contains(text, CHANGES_END_STRING):
+		case IsUnchangedHiddenLine(text):
+			if err := result.Hidden.addFromLine(text); err != nil {
+				return nil, err
+			}
+		case IsResourceCommentLine(text), IsResourceTerminator(text), strings.Contains(text, CHANGES_END_STRING):
+			// objects consume their own "}", so a "}" here ends the resource or block containing the attribute
 			return nil, fmt.Errorf("unexpected line while parsing jsonencode attribute: %s", text)
 		case IsMapAttributeChangeLine(text):
-			ma, err := parseMapAttribute(s)
+			ma, err := parseMapAttribute(s, cfg)
 			if err != nil {
 				return nil, err
 			}
+			setJSONCollectionKind(ma)
 			result.AttributeChanges = append(result.AttributeChanges, ma)
 		case IsArrayAttributeChangeLine(text):
-			aa, err := parseArrayAttribute(s)
+			aa, err := parseArrayAttribute(s, cfg)
 			if err != nil {
 				return nil, err
 			}
+			setJSONCollectionKind(aa)
 			result.AttributeChanges = append(result.AttributeChanges, aa)
 		case IsHeredocAttributeChangeLine(text):
 			// TODO: check if this is even allowed by terraform
@@ -255,7 +592,13 @@ func parseJSONEncodeAttribute(s *bufio.Scanner) (*JSONEncodeAttributeChange, err
 			result.AttributeChanges = append(result.AttributeChanges, ha)
 		case IsAttributeChangeLine(text):
 			// TODO: check if this is even allowed by terraform
-			ac, err := NewAttributeChangeFromLine(text)
+			ac, err := newAttributeChangeFromLine(text, cfg)
+			if err != nil {
+				return nil, err
+			}
+			result.AttributeChanges = append(result.AttributeChanges, ac)
+		case IsJSONEncodeValueLine(text):
+			ac, err := newJSONEncodeValueFromLine(text, result.UpdateType, cfg)
 			if err != nil {
 				return nil, err
 			}
@@ -267,25 +610,39 @@ func parseJSONEncodeAttribute(s *bufio.Scanner) (*JSONEncodeAttributeChange, err
 }
 
 func parseHeredocAttribute(s *bufio.Scanner) (*HeredocAttributeChange, error) {
-	normalized := formatInput(s.Bytes())
-	result, err := NewHeredocAttributeChangeFromLine(normalized)
+	header := uncolor(s.Bytes())
+	result, err := NewHeredocAttributeChangeFromLine(strings.TrimSpace(header))
 	if err != nil {
 		return nil, err
 	}
+
+	// the change symbol of each line is indented past the change symbol of the attribute
+	indent := len(header) - len(strings.TrimLeft(header, " ")) + HEREDOC_INDENT
 	for s.Scan() {
-		text := formatInput(s.Bytes())
+		line := uncolor(s.Bytes())
+		text := strings.TrimSpace(line)
 		if IsHeredocAttributeTerminator(text) {
+			result.Computed = isComputedTerminator(text)
+			result.Sensitive = isSensitiveTerminator(text)
 			return result, nil
 		}
 
-		// TODO: should not trim space for heredoc, but only trim the indent
-		// TODO: it's also hard to determine if a line was deleted or is a list in an array
-		result.AddLineToContent(text)
+		result.AddLineToContent(trimIndent(line, indent))
 	}
 
 	return nil, fmt.Errorf("unexpected end of input while parsing heredoc attribute")
 }
 
+// trimIndent removes up to n leading spaces from the line, so any indentation of the content is kept
+func trimIndent(line string, n int) string {
+	i := 0
+	for i < n && i < len(line) && line[i] == ' ' {
+		i++
+	}
+
+	return line[i:]
+}
+
 func formatInput(input []byte) string {
 	return strings.TrimSpace(uncolor(input))
 }
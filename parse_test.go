@@ -1,6 +1,7 @@
 package tfplanparse
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -31,13 +32,14 @@ func TestParse(t *testing.T) {
 				&ResourceChange{
 					Address:       "module.my-module.github_team_membership.member",
 					ModuleAddress: "module.my-module",
+					Mode:          ManagedResourceMode,
 					Type:          "github_team_membership",
 					Name:          "member",
 					UpdateType:    DestroyResource,
-					AttributeChanges: []attributeChange{
+					AttributeChanges: []Attr{
 						&AttributeChange{
 							Name:       "etag",
-							OldValue:   `W/\"etag-0\"`,
+							OldValue:   `W/"etag-0"`,
 							NewValue:   nil,
 							UpdateType: DestroyResource,
 						},
@@ -70,14 +72,15 @@ func TestParse(t *testing.T) {
 				&ResourceChange{
 					Address:       "module.my-module.github_team_membership.member[1]",
 					ModuleAddress: "module.my-module",
+					Mode:          ManagedResourceMode,
 					Type:          "github_team_membership",
 					Name:          "member",
 					Index:         1,
 					UpdateType:    DestroyResource,
-					AttributeChanges: []attributeChange{
+					AttributeChanges: []Attr{
 						&AttributeChange{
 							Name:       "etag",
-							OldValue:   `W/\"etag-1\"`,
+							OldValue:   `W/"etag-1"`,
 							NewValue:   nil,
 							UpdateType: DestroyResource,
 						},
@@ -110,14 +113,15 @@ func TestParse(t *testing.T) {
 				&ResourceChange{
 					Address:       "module.my-module.github_team_membership.member[2]",
 					ModuleAddress: "module.my-module",
+					Mode:          ManagedResourceMode,
 					Type:          "github_team_membership",
 					Name:          "member",
 					Index:         2,
 					UpdateType:    DestroyResource,
-					AttributeChanges: []attributeChange{
+					AttributeChanges: []Attr{
 						&AttributeChange{
 							Name:       "etag",
-							OldValue:   `W/\"etag-2\"`,
+							OldValue:   `W/"etag-2"`,
 							NewValue:   nil,
 							UpdateType: DestroyResource,
 						},
@@ -150,14 +154,15 @@ func TestParse(t *testing.T) {
 				&ResourceChange{
 					Address:       "module.my-module.github_team_membership.member[3]",
 					ModuleAddress: "module.my-module",
+					Mode:          ManagedResourceMode,
 					Type:          "github_team_membership",
 					Name:          "member",
 					Index:         3,
 					UpdateType:    DestroyResource,
-					AttributeChanges: []attributeChange{
+					AttributeChanges: []Attr{
 						&AttributeChange{
 							Name:       "etag",
-							OldValue:   `W/\"etag-3\"`,
+							OldValue:   `W/"etag-3"`,
 							NewValue:   nil,
 							UpdateType: DestroyResource,
 						},
@@ -195,11 +200,12 @@ func TestParse(t *testing.T) {
 				&ResourceChange{
 					Address:       "module.my-project.google_project_services.gcp_enabled_services[0]",
 					ModuleAddress: "module.my-project",
+					Mode:          ManagedResourceMode,
 					Type:          "google_project_services",
 					Name:          "gcp_enabled_services",
 					Index:         0,
 					UpdateType:    DestroyResource,
-					AttributeChanges: []attributeChange{
+					AttributeChanges: []Attr{
 						&AttributeChange{
 							Name:       "disable_on_destroy",
 							OldValue:   false,
@@ -220,7 +226,7 @@ func TestParse(t *testing.T) {
 						},
 						&ArrayAttributeChange{
 							Name: "services",
-							AttributeChanges: []attributeChange{
+							AttributeChanges: []Attr{
 								&AttributeChange{
 									OldValue:   "appengine.googleapis.com",
 									NewValue:   nil,
@@ -233,8 +239,9 @@ func TestParse(t *testing.T) {
 								},
 							},
 							UpdateType: DestroyResource,
+							Kind:       ListCollection,
 						},
-						&MapAttributeChange{
+						&BlockChange{
 							Name:       "timeouts",
 							UpdateType: DestroyResource,
 						},
@@ -248,22 +255,24 @@ func TestParse(t *testing.T) {
 				&ResourceChange{
 					Address:       "module.mymodule.kubernetes_namespace.mynamespace",
 					ModuleAddress: "module.mymodule",
+					Mode:          ManagedResourceMode,
 					Type:          "kubernetes_namespace",
 					Name:          "mynamespace",
 					UpdateType:    UpdateInPlaceResource,
-					AttributeChanges: []attributeChange{
+					AttributeChanges: []Attr{
 						&AttributeChange{
 							Name:       "id",
 							OldValue:   "namespace-id",
 							NewValue:   "namespace-id",
 							UpdateType: NoOpResource,
 						},
-						&MapAttributeChange{
+						&BlockChange{
 							Name: "metadata",
-							AttributeChanges: []attributeChange{
+							AttributeChanges: []Attr{
 								&MapAttributeChange{
 									Name:       "annotations",
 									UpdateType: NoOpResource,
+									Kind:       MapCollection,
 								},
 								&AttributeChange{
 									Name:       "generation",
@@ -273,7 +282,7 @@ func TestParse(t *testing.T) {
 								},
 								&MapAttributeChange{
 									Name: "labels",
-									AttributeChanges: []attributeChange{
+									AttributeChanges: []Attr{
 										&AttributeChange{
 											Name:       "label",
 											OldValue:   "value",
@@ -294,6 +303,7 @@ func TestParse(t *testing.T) {
 										},
 									},
 									UpdateType: UpdateInPlaceResource,
+									Kind:       MapCollection,
 								},
 								&AttributeChange{
 									Name:       "name",
@@ -322,7 +332,7 @@ func TestParse(t *testing.T) {
 							},
 							UpdateType: UpdateInPlaceResource,
 						},
-						&MapAttributeChange{
+						&BlockChange{
 							Name:       "timeouts",
 							UpdateType: NoOpResource,
 						},
@@ -336,23 +346,24 @@ func TestParse(t *testing.T) {
 				&ResourceChange{
 					Address:       "module.mymodule.kubernetes_role_binding.user_is_edit",
 					ModuleAddress: "module.mymodule",
+					Mode:          ManagedResourceMode,
 					Type:          "kubernetes_role_binding",
 					Name:          "user_is_edit",
 					UpdateType:    NewResource,
-					AttributeChanges: []attributeChange{
+					AttributeChanges: []Attr{
 						&AttributeChange{
 							Name:       "id",
 							OldValue:   nil,
-							NewValue:   "(known after apply)",
+							NewValue:   UnknownMarker,
 							UpdateType: NewResource,
 						},
-						&MapAttributeChange{
+						&BlockChange{
 							Name: "metadata",
-							AttributeChanges: []attributeChange{
+							AttributeChanges: []Attr{
 								&AttributeChange{
 									Name:       "generation",
 									OldValue:   nil,
-									NewValue:   "(known after apply)",
+									NewValue:   UnknownMarker,
 									UpdateType: NewResource,
 								},
 								&AttributeChange{
@@ -370,27 +381,27 @@ func TestParse(t *testing.T) {
 								&AttributeChange{
 									Name:       "resource_version",
 									OldValue:   nil,
-									NewValue:   "(known after apply)",
+									NewValue:   UnknownMarker,
 									UpdateType: NewResource,
 								},
 								&AttributeChange{
 									Name:       "self_link",
 									OldValue:   nil,
-									NewValue:   "(known after apply)",
+									NewValue:   UnknownMarker,
 									UpdateType: NewResource,
 								},
 								&AttributeChange{
 									Name:       "uid",
 									OldValue:   nil,
-									NewValue:   "(known after apply)",
+									NewValue:   UnknownMarker,
 									UpdateType: NewResource,
 								},
 							},
 							UpdateType: NewResource,
 						},
-						&MapAttributeChange{
+						&BlockChange{
 							Name: "role_ref",
-							AttributeChanges: []attributeChange{
+							AttributeChanges: []Attr{
 								&AttributeChange{
 									Name:       "api_group",
 									OldValue:   nil,
@@ -412,9 +423,9 @@ func TestParse(t *testing.T) {
 							},
 							UpdateType: NewResource,
 						},
-						&MapAttributeChange{
+						&BlockChange{
 							Name: "subject",
-							AttributeChanges: []attributeChange{
+							AttributeChanges: []Attr{
 								&AttributeChange{
 									Name:       "api_group",
 									OldValue:   nil,
@@ -441,22 +452,23 @@ func TestParse(t *testing.T) {
 				&ResourceChange{
 					Address:       "module.mymodule.kubernetes_role_binding.user_is_view",
 					ModuleAddress: "module.mymodule",
+					Mode:          ManagedResourceMode,
 					Type:          "kubernetes_role_binding",
 					Name:          "user_is_view",
 					UpdateType:    DestroyResource,
-					AttributeChanges: []attributeChange{
+					AttributeChanges: []Attr{
 						&AttributeChange{
 							Name:       "id",
 							OldValue:   "my-namespace/user_is_view",
 							NewValue:   nil,
 							UpdateType: DestroyResource,
 						},
-						&MapAttributeChange{
+						&BlockChange{
 							Name: "metadata",
-							AttributeChanges: []attributeChange{
+							AttributeChanges: []Attr{
 								&MapAttributeChange{
 									Name: "annotations",
-									AttributeChanges: []attributeChange{
+									AttributeChanges: []Attr{
 										&AttributeChange{
 											Name:       "my-annotation",
 											OldValue:   "annot",
@@ -465,6 +477,7 @@ func TestParse(t *testing.T) {
 										},
 									},
 									UpdateType: DestroyResource,
+									Kind:       MapCollection,
 								},
 								&AttributeChange{
 									Name:       "generation",
@@ -474,7 +487,7 @@ func TestParse(t *testing.T) {
 								},
 								&AttributeChange{
 									Name:       "labels",
-									OldValue:   nil,
+									OldValue:   map[string]interface{}{},
 									NewValue:   nil,
 									UpdateType: DestroyResource,
 								},
@@ -511,9 +524,9 @@ func TestParse(t *testing.T) {
 							},
 							UpdateType: DestroyResource,
 						},
-						&MapAttributeChange{
+						&BlockChange{
 							Name: "role_ref",
-							AttributeChanges: []attributeChange{
+							AttributeChanges: []Attr{
 								&AttributeChange{
 									Name:       "api_group",
 									OldValue:   "rbac.authorization.k8s.io",
@@ -535,9 +548,9 @@ func TestParse(t *testing.T) {
 							},
 							UpdateType: DestroyResource,
 						},
-						&MapAttributeChange{
+						&BlockChange{
 							Name: "subject",
-							AttributeChanges: []attributeChange{
+							AttributeChanges: []Attr{
 								&AttributeChange{
 									Name:       "api_group",
 									OldValue:   "rbac.authorization.k8s.io",
@@ -569,27 +582,28 @@ func TestParse(t *testing.T) {
 				&ResourceChange{
 					Address:       "module.mymodule.kubernetes_role_binding.user_is_view",
 					ModuleAddress: "module.mymodule",
+					Mode:          ManagedResourceMode,
 					Type:          "kubernetes_role_binding",
 					Name:          "user_is_view",
 					UpdateType:    DestroyResource,
-					AttributeChanges: []attributeChange{
+					AttributeChanges: []Attr{
 						&AttributeChange{
 							Name:       "id",
 							OldValue:   "my-namespace/user_is_view",
 							NewValue:   nil,
 							UpdateType: DestroyResource,
 						},
-						&MapAttributeChange{
+						&BlockChange{
 							Name: "metadata",
-							AttributeChanges: []attributeChange{
+							AttributeChanges: []Attr{
 								&MapAttributeChange{
 									Name: "annotations",
-									AttributeChanges: []attributeChange{
+									AttributeChanges: []Attr{
 										&JSONEncodeAttributeChange{
 											Name: "encoded",
-											AttributeChanges: []attributeChange{
+											AttributeChanges: []Attr{
 												&MapAttributeChange{
-													AttributeChanges: []attributeChange{
+													AttributeChanges: []Attr{
 														&AttributeChange{
 															Name:       "apiVersion",
 															OldValue:   "rbac.authorization.k8s.io/v1",
@@ -604,10 +618,10 @@ func TestParse(t *testing.T) {
 														},
 														&MapAttributeChange{
 															Name: "metadata",
-															AttributeChanges: []attributeChange{
+															AttributeChanges: []Attr{
 																&MapAttributeChange{
 																	Name: "annotations",
-																	AttributeChanges: []attributeChange{
+																	AttributeChanges: []Attr{
 																		&AttributeChange{
 																			Name:       "my-annotation",
 																			OldValue:   "annot",
@@ -616,16 +630,17 @@ func TestParse(t *testing.T) {
 																		},
 																	},
 																	UpdateType: DestroyResource,
+																	Kind:       ObjectCollection,
 																},
 																&AttributeChange{
 																	Name:       "creationTimestamp",
-																	OldValue:   "null",
+																	OldValue:   nil,
 																	NewValue:   nil,
 																	UpdateType: DestroyResource,
 																},
 																&MapAttributeChange{
 																	Name: "labels",
-																	AttributeChanges: []attributeChange{
+																	AttributeChanges: []Attr{
 																		&AttributeChange{
 																			Name:       "my-label",
 																			OldValue:   "label",
@@ -634,6 +649,7 @@ func TestParse(t *testing.T) {
 																		},
 																	},
 																	UpdateType: DestroyResource,
+																	Kind:       ObjectCollection,
 																},
 																&AttributeChange{
 																	Name:       "name",
@@ -649,10 +665,11 @@ func TestParse(t *testing.T) {
 																},
 															},
 															UpdateType: DestroyResource,
+															Kind:       ObjectCollection,
 														},
 														&MapAttributeChange{
 															Name: "roleRef",
-															AttributeChanges: []attributeChange{
+															AttributeChanges: []Attr{
 																&AttributeChange{
 																	Name:       "apiGroup",
 																	OldValue:   "rbac.authorization.k8s.io",
@@ -673,13 +690,14 @@ func TestParse(t *testing.T) {
 																},
 															},
 															UpdateType: DestroyResource,
+															Kind:       ObjectCollection,
 														},
 														&ArrayAttributeChange{
 															Name: "subjects",
-															AttributeChanges: []attributeChange{
+															AttributeChanges: []Attr{
 																&MapAttributeChange{
 																	Name: "",
-																	AttributeChanges: []attributeChange{
+																	AttributeChanges: []Attr{
 																		&AttributeChange{
 																			Name:       "apiGroup",
 																			OldValue:   "rbac.authorization.k8s.io",
@@ -700,19 +718,23 @@ func TestParse(t *testing.T) {
 																		},
 																	},
 																	UpdateType: DestroyResource,
+																	Kind:       ObjectCollection,
 																},
 															},
 															UpdateType: DestroyResource,
+															Kind:       TupleCollection,
 														},
 													},
 													// TODO: this should be DestroyResource
 													UpdateType: NoOpResource,
+													Kind:       ObjectCollection,
 												},
 											},
 											UpdateType: DestroyResource,
 										},
 									},
 									UpdateType: DestroyResource,
+									Kind:       MapCollection,
 								},
 								&AttributeChange{
 									Name:       "generation",
@@ -722,7 +744,7 @@ func TestParse(t *testing.T) {
 								},
 								&AttributeChange{
 									Name:       "labels",
-									OldValue:   nil,
+									OldValue:   map[string]interface{}{},
 									NewValue:   nil,
 									UpdateType: DestroyResource,
 								},
@@ -759,9 +781,9 @@ func TestParse(t *testing.T) {
 							},
 							UpdateType: DestroyResource,
 						},
-						&MapAttributeChange{
+						&BlockChange{
 							Name: "role_ref",
-							AttributeChanges: []attributeChange{
+							AttributeChanges: []Attr{
 								&AttributeChange{
 									Name:       "api_group",
 									OldValue:   "rbac.authorization.k8s.io",
@@ -783,9 +805,9 @@ func TestParse(t *testing.T) {
 							},
 							UpdateType: DestroyResource,
 						},
-						&MapAttributeChange{
+						&BlockChange{
 							Name: "subject",
-							AttributeChanges: []attributeChange{
+							AttributeChanges: []Attr{
 								&AttributeChange{
 									Name:       "api_group",
 									OldValue:   "rbac.authorization.k8s.io",
@@ -811,6 +833,76 @@ func TestParse(t *testing.T) {
 				},
 			},
 		},
+		"unchanged values hidden": {
+			file: "test/hidden.stdout",
+			expected: []*ResourceChange{
+				&ResourceChange{
+					Address:    "aws_instance.web",
+					Mode:       ManagedResourceMode,
+					Type:       "aws_instance",
+					Name:       "web",
+					UpdateType: UpdateInPlaceResource,
+					AttributeChanges: []Attr{
+						&AttributeChange{
+							Name:       "id",
+							OldValue:   "i-0123456789abcdef0",
+							NewValue:   "i-0123456789abcdef0",
+							UpdateType: NoOpResource,
+						},
+						&MapAttributeChange{
+							Name: "tags",
+							AttributeChanges: []Attr{
+								&AttributeChange{
+									Name:       "Name",
+									OldValue:   "web",
+									NewValue:   "web-1",
+									UpdateType: UpdateInPlaceResource,
+								},
+							},
+							UpdateType: UpdateInPlaceResource,
+							Hidden: HiddenCount{
+								Elements: 2,
+							},
+							Kind: MapCollection,
+						},
+						&ArrayAttributeChange{
+							Name: "vpc_security_group_ids",
+							AttributeChanges: []Attr{
+								&AttributeChange{
+									OldValue:   nil,
+									NewValue:   "sg-0456",
+									UpdateType: NewResource,
+								},
+							},
+							UpdateType: UpdateInPlaceResource,
+							Hidden: HiddenCount{
+								Elements: 1,
+							},
+							Kind: ListCollection,
+						},
+						&BlockChange{
+							Name: "root_block_device",
+							AttributeChanges: []Attr{
+								&AttributeChange{
+									Name:       "volume_size",
+									OldValue:   8,
+									NewValue:   16,
+									UpdateType: UpdateInPlaceResource,
+								},
+							},
+							UpdateType: UpdateInPlaceResource,
+							Hidden: HiddenCount{
+								Attributes: 7,
+							},
+						},
+					},
+					Hidden: HiddenCount{
+						Attributes: 28,
+						Blocks:     4,
+					},
+				},
+			},
+		},
 	}
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
@@ -825,3 +917,1327 @@ func TestParse(t *testing.T) {
 		})
 	}
 }
+
+func TestParsePlanDialect(t *testing.T) {
+	v1ResourceChanges := []*ResourceChange{
+		&ResourceChange{
+			Address:    "aws_s3_bucket.logs",
+			Mode:       ManagedResourceMode,
+			Type:       "aws_s3_bucket",
+			Name:       "logs",
+			UpdateType: NewResource,
+			AttributeChanges: []Attr{
+				&AttributeChange{
+					Name:       "arn",
+					OldValue:   nil,
+					NewValue:   UnknownMarker,
+					UpdateType: NewResource,
+				},
+				&AttributeChange{
+					Name:       "bucket",
+					OldValue:   nil,
+					NewValue:   "my-logs",
+					UpdateType: NewResource,
+				},
+				&AttributeChange{
+					Name:       "force_destroy",
+					OldValue:   nil,
+					NewValue:   false,
+					UpdateType: NewResource,
+				},
+				&AttributeChange{
+					Name:       "id",
+					OldValue:   nil,
+					NewValue:   UnknownMarker,
+					UpdateType: NewResource,
+				},
+			},
+		},
+	}
+
+	cases := map[string]struct {
+		file            string
+		dialect         Dialect
+		resourceChanges []*ResourceChange
+	}{
+		"legacy": {
+			file:    "test/array.stdout",
+			dialect: LegacyTerraformDialect,
+		},
+		"legacy no changes": {
+			file:            "test/nochanges.stdout",
+			dialect:         LegacyTerraformDialect,
+			resourceChanges: []*ResourceChange{},
+		},
+		"v1": {
+			file:            "test/v1.stdout",
+			dialect:         TerraformDialect,
+			resourceChanges: v1ResourceChanges,
+		},
+		"v1 no changes": {
+			file:            "test/nochanges_v1.stdout",
+			dialect:         TerraformDialect,
+			resourceChanges: []*ResourceChange{},
+		},
+		"opentofu": {
+			file:            "test/opentofu.stdout",
+			dialect:         OpenTofuDialect,
+			resourceChanges: v1ResourceChanges,
+		},
+		"opentofu no changes": {
+			file:            "test/nochanges_opentofu.stdout",
+			dialect:         OpenTofuDialect,
+			resourceChanges: []*ResourceChange{},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParsePlanFromFile(tc.file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Dialect != tc.dialect {
+				t.Errorf("Expected: %v but got %v", tc.dialect, got.Dialect)
+			}
+			if tc.resourceChanges == nil {
+				return
+			}
+			if diff := cmp.Diff(got.ResourceChanges, tc.resourceChanges); diff != "" {
+				t.Errorf("(-got, +expected)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParsePlanOutputs(t *testing.T) {
+	cases := map[string]struct {
+		file            string
+		resourceChanges int
+		expected        []*OutputChange
+	}{
+		"no outputs": {
+			file:            "test/v1.stdout",
+			resourceChanges: 1,
+			expected:        []*OutputChange{},
+		},
+		"resources and outputs": {
+			file:            "test/outputs.stdout",
+			resourceChanges: 1,
+			expected: []*OutputChange{
+				&OutputChange{
+					Name:       "bucket_id",
+					UpdateType: NewResource,
+					Change: &AttributeChange{
+						Name:       "bucket_id",
+						OldValue:   nil,
+						NewValue:   UnknownMarker,
+						UpdateType: NewResource,
+					},
+				},
+				&OutputChange{
+					Name:       "endpoint",
+					UpdateType: UpdateInPlaceResource,
+					Change: &AttributeChange{
+						Name:       "endpoint",
+						OldValue:   "https://old.example.com",
+						NewValue:   "https://new.example.com",
+						UpdateType: UpdateInPlaceResource,
+					},
+				},
+				&OutputChange{
+					Name:       "legacy",
+					UpdateType: DestroyResource,
+					Change: &AttributeChange{
+						Name:       "legacy",
+						OldValue:   "legacy",
+						NewValue:   nil,
+						UpdateType: DestroyResource,
+					},
+				},
+				&OutputChange{
+					Name:       "secret",
+					UpdateType: NewResource,
+					Change: &AttributeChange{
+						Name:           "secret",
+						OldValue:       nil,
+						NewValue:       SensitiveMarker,
+						UpdateType:     NewResource,
+						AfterSensitive: true,
+					},
+				},
+				&OutputChange{
+					Name:       "tags",
+					UpdateType: NewResource,
+					Change: &MapAttributeChange{
+						Name: "tags",
+						AttributeChanges: []Attr{
+							&AttributeChange{
+								Name:       "env",
+								OldValue:   nil,
+								NewValue:   "prod",
+								UpdateType: NewResource,
+							},
+						},
+						UpdateType: NewResource,
+						Kind:       MapCollection,
+					},
+				},
+				&OutputChange{
+					Name:       "zones",
+					UpdateType: NewResource,
+					Change: &ArrayAttributeChange{
+						Name: "zones",
+						AttributeChanges: []Attr{
+							&AttributeChange{
+								OldValue:   nil,
+								NewValue:   "us-east-1a",
+								UpdateType: NewResource,
+							},
+							&AttributeChange{
+								OldValue:   nil,
+								NewValue:   "us-east-1b",
+								UpdateType: NewResource,
+							},
+						},
+						Kind:       ListCollection,
+						UpdateType: NewResource,
+					},
+				},
+			},
+		},
+		"outputs only": {
+			file:            "test/outputsonly.stdout",
+			resourceChanges: 0,
+			expected: []*OutputChange{
+				&OutputChange{
+					Name:       "endpoint",
+					UpdateType: UpdateInPlaceResource,
+					Change: &AttributeChange{
+						Name:       "endpoint",
+						OldValue:   "https://old.example.com",
+						NewValue:   "https://new.example.com",
+						UpdateType: UpdateInPlaceResource,
+					},
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParsePlanFromFile(tc.file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got.ResourceChanges) != tc.resourceChanges {
+				t.Errorf("Expected %d resource changes but got %d", tc.resourceChanges, len(got.ResourceChanges))
+			}
+			if diff := cmp.Diff(got.OutputChanges, tc.expected); diff != "" {
+				t.Errorf("(-got, +expected)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParsePlanDiagnostics(t *testing.T) {
+	cases := map[string]struct {
+		file            string
+		shouldError     bool
+		resourceChanges int
+		expected        []*Diagnostic
+	}{
+		"no diagnostics": {
+			file:            "test/v1.stdout",
+			shouldError:     false,
+			resourceChanges: 1,
+			expected:        []*Diagnostic{},
+		},
+		"legacy error": {
+			file:            "test/error.stdout",
+			shouldError:     true,
+			resourceChanges: 0,
+			expected: []*Diagnostic{
+				&Diagnostic{
+					Severity: ErrorSeverity,
+					Summary:  "Reference to undeclared resource",
+					Detail: []string{
+						`A managed resource "aws_subnet" "missing" has not been declared in the root`,
+						"module.",
+					},
+					Source: &DiagnosticSource{
+						Filename: "main.tf",
+						Line:     12,
+						Context:  `resource "aws_instance" "web"`,
+					},
+				},
+			},
+		},
+		"errors": {
+			file:            "test/error_v1.stdout",
+			shouldError:     true,
+			resourceChanges: 0,
+			expected: []*Diagnostic{
+				&Diagnostic{
+					Severity: ErrorSeverity,
+					Summary:  "Invalid value for variable",
+					Detail: []string{
+						"The instance count must be positive.",
+						"This was checked by the validation rule at main.tf:6,3-13.",
+					},
+					Source: &DiagnosticSource{
+						Filename: "main.tf",
+						Line:     3,
+						Context:  `variable "instance_count"`,
+					},
+				},
+				&Diagnostic{
+					Severity: ErrorSeverity,
+					Summary:  "No valid credential sources found",
+					Detail: []string{
+						"Please see https://registry.terraform.io/providers/hashicorp/aws",
+						"for more information about providing credentials.",
+					},
+				},
+			},
+		},
+		"warnings": {
+			file:            "test/warning_v1.stdout",
+			shouldError:     false,
+			resourceChanges: 1,
+			expected: []*Diagnostic{
+				&Diagnostic{
+					Severity: WarningSeverity,
+					Summary:  "Argument is deprecated",
+					Detail: []string{
+						"Use the aws_s3_bucket_acl resource instead",
+					},
+					Source: &DiagnosticSource{
+						Filename: "main.tf",
+						Line:     1,
+						Context:  `resource "aws_s3_bucket" "logs"`,
+					},
+				},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParsePlanFromFile(tc.file)
+			if tc.shouldError {
+				if _, ok := err.(*PlanError); !ok {
+					t.Fatalf("Expected a PlanError but got %v", err)
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if len(got.ResourceChanges) != tc.resourceChanges {
+				t.Errorf("Expected %d resource changes but got %d", tc.resourceChanges, len(got.ResourceChanges))
+			}
+			if diff := cmp.Diff(got.Diagnostics, tc.expected); diff != "" {
+				t.Errorf("(-got, +expected)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParsePlanDrift(t *testing.T) {
+	got, err := ParsePlanFromFile("test/drift.stdout")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedDrift := []*ResourceChange{
+		&ResourceChange{
+			Address:    "aws_instance.web",
+			Mode:       ManagedResourceMode,
+			Type:       "aws_instance",
+			Name:       "web",
+			UpdateType: ChangedOutsideResource,
+			AttributeChanges: []Attr{
+				&AttributeChange{
+					Name:       "id",
+					OldValue:   "i-0123456789abcdef0",
+					NewValue:   "i-0123456789abcdef0",
+					UpdateType: NoOpResource,
+				},
+				&MapAttributeChange{
+					Name: "tags",
+					AttributeChanges: []Attr{
+						&AttributeChange{
+							Name:       "Owner",
+							OldValue:   nil,
+							NewValue:   "someone",
+							UpdateType: NewResource,
+						},
+					},
+					UpdateType: UpdateInPlaceResource,
+					Kind:       MapCollection,
+				},
+			},
+			Hidden: HiddenCount{
+				Attributes: 28,
+			},
+		},
+		&ResourceChange{
+			Address:    "aws_instance.worker",
+			Mode:       ManagedResourceMode,
+			Type:       "aws_instance",
+			Name:       "worker",
+			UpdateType: DeletedOutsideResource,
+			AttributeChanges: []Attr{
+				&AttributeChange{
+					Name:       "id",
+					OldValue:   "i-0fedcba9876543210",
+					NewValue:   nil,
+					UpdateType: DestroyResource,
+				},
+			},
+			Hidden: HiddenCount{
+				Attributes: 29,
+			},
+		},
+	}
+	if diff := cmp.Diff(got.DriftChanges, expectedDrift); diff != "" {
+		t.Errorf("(-got, +expected)\n%s", diff)
+	}
+
+	if len(got.ResourceChanges) != 1 {
+		t.Fatalf("Expected 1 resource change but got %d", len(got.ResourceChanges))
+	}
+	if got.ResourceChanges[0].UpdateType != NewResource {
+		t.Errorf("Expected: %v but got %v", NewResource, got.ResourceChanges[0].UpdateType)
+	}
+}
+
+func TestParsePlanReplacementOrder(t *testing.T) {
+	got, err := ParsePlanFromFile("test/replace.stdout")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]ReplacementOrder{
+		"aws_instance.db":  DestroyBeforeCreate,
+		"aws_instance.web": CreateBeforeDestroy,
+	}
+	if len(got.ResourceChanges) != len(expected) {
+		t.Fatalf("Expected %d resource changes but got %d", len(expected), len(got.ResourceChanges))
+	}
+	for _, rc := range got.ResourceChanges {
+		if rc.ReplacementOrder != expected[rc.Address] {
+			t.Errorf("%s: Expected: %v but got %v", rc.Address, expected[rc.Address], rc.ReplacementOrder)
+		}
+	}
+
+	if _, err := ParsePlanFromFile("test/replace_mismatch.stdout"); err == nil {
+		t.Errorf("Expected an error for a mismatched resource change symbol but didn't get one")
+	}
+}
+
+func TestParsePlanMovedImportedForgotten(t *testing.T) {
+	got, err := ParsePlanFromFile("test/moved.stdout")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type resourceInfo struct {
+		Address         string
+		PreviousAddress string
+		ImportID        string
+		UpdateType      UpdateType
+		Attributes      int
+	}
+	expected := []resourceInfo{
+		{Address: "aws_instance.b", PreviousAddress: "aws_instance.a", UpdateType: MoveResource, Attributes: 2},
+		{Address: "aws_instance.d", PreviousAddress: "aws_instance.c", UpdateType: UpdateInPlaceResource, Attributes: 2},
+		{Address: "aws_s3_bucket.logs", UpdateType: ImportResource, Attributes: 2},
+		{Address: "aws_s3_bucket.data", ImportID: "my-data", UpdateType: UpdateInPlaceResource, Attributes: 3},
+		{Address: "aws_instance.legacy", UpdateType: ForgetResource, Attributes: 1},
+	}
+
+	result := []resourceInfo{}
+	for _, rc := range got.ResourceChanges {
+		result = append(result, resourceInfo{
+			Address:         rc.Address,
+			PreviousAddress: rc.PreviousAddress,
+			ImportID:        rc.ImportID,
+			UpdateType:      rc.UpdateType,
+			Attributes:      len(rc.AttributeChanges),
+		})
+	}
+	if diff := cmp.Diff(result, expected); diff != "" {
+		t.Errorf("(-got, +expected)\n%s", diff)
+	}
+}
+
+func TestParsePlanActionReasons(t *testing.T) {
+	got, err := ParsePlanFromFile("test/reasons.stdout")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type reasonInfo struct {
+		Address          string
+		ActionReason     ActionReason
+		ActionReasonText string
+	}
+	expected := []reasonInfo{
+		{Address: "data.aws_ami.latest", ActionReason: ReadBecauseDependencyPending, ActionReasonText: "depends on a resource or a module with changes pending"},
+		{Address: "aws_instance.old", ActionReason: DeleteBecauseNoResourceConfig, ActionReasonText: "because aws_instance.old is not in configuration"},
+		{Address: `aws_instance.web["b"]`, ActionReason: DeleteBecauseEachKey, ActionReasonText: `because key ["b"] is not in for_each map`},
+		{Address: "module.legacy.aws_instance.app", ActionReason: DeleteBecauseNoModule, ActionReasonText: "because module.legacy is not in configuration"},
+		{Address: "aws_instance.cache", ActionReason: ReplaceBecauseCannotUpdate},
+		{Address: "aws_instance.queue", ActionReason: ReplaceByRequest},
+		{Address: "aws_instance.worker", ActionReason: ReplaceByTriggers},
+	}
+
+	result := []reasonInfo{}
+	for _, rc := range got.ResourceChanges {
+		result = append(result, reasonInfo{
+			Address:          rc.Address,
+			ActionReason:     rc.ActionReason,
+			ActionReasonText: rc.ActionReasonText,
+		})
+	}
+	if diff := cmp.Diff(result, expected); diff != "" {
+		t.Errorf("(-got, +expected)\n%s", diff)
+	}
+}
+
+func TestParsePlanDataSources(t *testing.T) {
+	got, err := ParsePlanFromFile("test/datasources.stdout")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type resourceInfo struct {
+		Address       string
+		ModuleAddress string
+		Mode          ResourceMode
+		Type          string
+		Name          string
+		UpdateType    UpdateType
+		ActionReason  ActionReason
+	}
+	expected := []resourceInfo{
+		{Address: "data.aws_iam_policy_document.policy", Mode: DataResourceMode, Type: "aws_iam_policy_document", Name: "policy", UpdateType: ReadResource, ActionReason: ReadBecauseConfigUnknown},
+		{Address: "aws_iam_policy_document.policy", Mode: ManagedResourceMode, Type: "aws_iam_policy_document", Name: "policy", UpdateType: NewResource},
+		{Address: "module.network.data.aws_vpc.main", ModuleAddress: "module.network", Mode: DataResourceMode, Type: "aws_vpc", Name: "main", UpdateType: ReadResource, ActionReason: ReadBecauseDependencyPending},
+	}
+
+	result := []resourceInfo{}
+	for _, rc := range got.ResourceChanges {
+		result = append(result, resourceInfo{
+			Address:       rc.Address,
+			ModuleAddress: rc.ModuleAddress,
+			Mode:          rc.Mode,
+			Type:          rc.Type,
+			Name:          rc.Name,
+			UpdateType:    rc.UpdateType,
+			ActionReason:  rc.ActionReason,
+		})
+	}
+	if diff := cmp.Diff(result, expected); diff != "" {
+		t.Errorf("(-got, +expected)\n%s", diff)
+	}
+
+	managed := FilterResourceChanges(got.ResourceChanges, IgnoreDataSources)
+	if len(managed) != 1 || managed[0].Address != "aws_iam_policy_document.policy" {
+		t.Errorf("Expected only aws_iam_policy_document.policy but got %v", managed)
+	}
+
+	if _, err := ParsePlanFromFile("test/datasources_mismatch.stdout"); err == nil {
+		t.Errorf("Expected an error for a mismatched resource mode but didn't get one")
+	}
+}
+
+func TestParsePlanDataSourcesBeforeV13(t *testing.T) {
+	got, err := ParsePlanFromFile("test/datasources_v012.stdout")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type resourceInfo struct {
+		Address      string
+		Mode         ResourceMode
+		UpdateType   UpdateType
+		ActionReason ActionReason
+	}
+	expected := []resourceInfo{
+		{Address: "data.aws_iam_policy_document.policy", Mode: DataResourceMode, UpdateType: ReadResource, ActionReason: ReadBecauseConfigUnknown},
+		{Address: "aws_s3_bucket.bucket", Mode: ManagedResourceMode, UpdateType: NewResource},
+	}
+
+	result := []resourceInfo{}
+	for _, rc := range got.ResourceChanges {
+		result = append(result, resourceInfo{
+			Address:      rc.Address,
+			Mode:         rc.Mode,
+			UpdateType:   rc.UpdateType,
+			ActionReason: rc.ActionReason,
+		})
+	}
+	if diff := cmp.Diff(result, expected); diff != "" {
+		t.Errorf("(-got, +expected)\n%s", diff)
+	}
+	if got.Summary == nil || got.Summary.Add != 1 {
+		t.Errorf("Expected the summary to be parsed but got %v", got.Summary)
+	}
+}
+
+func TestParsePlanDeposed(t *testing.T) {
+	got, err := ParsePlanFromFile("test/deposed.stdout")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type resourceInfo struct {
+		Address    string
+		Name       string
+		Index      interface{}
+		DeposedKey string
+	}
+	expected := []resourceInfo{
+		{Address: "aws_instance.web", Name: "web", DeposedKey: "1a2b3c4d"},
+		{Address: `module.app.aws_instance.worker["a.b"]`, Name: "worker", Index: "a.b", DeposedKey: "5e6f7a8b"},
+		{Address: "aws_instance.old", Name: "old"},
+	}
+
+	result := []resourceInfo{}
+	for _, rc := range got.ResourceChanges {
+		result = append(result, resourceInfo{
+			Address:    rc.Address,
+			Name:       rc.Name,
+			Index:      rc.Index,
+			DeposedKey: rc.DeposedKey,
+		})
+	}
+	if diff := cmp.Diff(result, expected); diff != "" {
+		t.Errorf("(-got, +expected)\n%s", diff)
+	}
+
+	if deposed := FilterResourceChanges(got.ResourceChanges, DeposedOnly); len(deposed) != 2 {
+		t.Errorf("Expected 2 deposed objects but got %d", len(deposed))
+	}
+}
+
+func TestParsePlanCollections(t *testing.T) {
+	got, err := ParsePlanFromFile("test/collections.stdout")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.ResourceChanges) != 1 {
+		t.Fatalf("Expected 1 resource change but got %d", len(got.ResourceChanges))
+	}
+
+	type collectionInfo struct {
+		Name       string
+		Kind       CollectionKind
+		UpdateType UpdateType
+		Elements   int
+	}
+	expected := []collectionInfo{
+		{Name: "ingress", Kind: SetCollection, UpdateType: UpdateInPlaceResource, Elements: 2},
+		{Name: "tags", Kind: MapCollection, UpdateType: UpdateInPlaceResource, Elements: 1},
+		{Name: "egress", Kind: SetCollection, UpdateType: DestroyResource},
+		{Name: "security_groups", Kind: ListCollection, UpdateType: NoOpResource},
+		{Name: "zones", Kind: ListCollection, UpdateType: DestroyResource, Elements: 1},
+	}
+
+	result := []collectionInfo{}
+	for _, a := range got.ResourceChanges[0].AttributeChanges {
+		switch ac := a.(type) {
+		case *ArrayAttributeChange:
+			result = append(result, collectionInfo{Name: ac.Name, Kind: ac.Kind, UpdateType: ac.UpdateType, Elements: len(ac.AttributeChanges)})
+		case *MapAttributeChange:
+			result = append(result, collectionInfo{Name: ac.Name, Kind: ac.Kind, UpdateType: ac.UpdateType, Elements: len(ac.AttributeChanges)})
+		}
+	}
+	if diff := cmp.Diff(result, expected); diff != "" {
+		t.Errorf("(-got, +expected)\n%s", diff)
+	}
+
+	ingress := got.ResourceChanges[0].AttributeChanges[1].(*ArrayAttributeChange)
+	for _, element := range ingress.AttributeChanges {
+		if kind := element.(*MapAttributeChange).Kind; kind != ObjectCollection {
+			t.Errorf("Expected: %v but got %v", ObjectCollection, kind)
+		}
+	}
+}
+
+func TestParsePlanReplacementCauses(t *testing.T) {
+	got, err := ParsePlanFromFile("test/forcesreplacement.stdout")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.ResourceChanges) != 1 {
+		t.Fatalf("Expected 1 resource change but got %d", len(got.ResourceChanges))
+	}
+
+	expected := []string{
+		"ami",
+		"placement_group",
+		"security_groups",
+		"ebs_block_device[0]",
+		"ebs_block_device[0]",
+		"root_block_device[0].volume_size",
+	}
+	if diff := cmp.Diff(got.ResourceChanges[0].ReplacementCauses(), expected); diff != "" {
+		t.Errorf("(-got, +expected)\n%s", diff)
+	}
+}
+
+func TestParsePlanNumberMode(t *testing.T) {
+	cases := map[string]struct {
+		opts     []ParseOption
+		expected map[string]interface{}
+	}{
+		"native numbers": {
+			expected: map[string]interface{}{
+				"id":             "012345678901",
+				"account_number": 123456789013,
+				"big_id":         1.2345678901234568e29,
+				"ratio":          float64(1000),
+				"port_numbers":   []interface{}{1000},
+			},
+		},
+		"exact numbers": {
+			opts: []ParseOption{WithNumberMode(ExactNumbers)},
+			expected: map[string]interface{}{
+				"id":             "012345678901",
+				"account_number": Number("123456789013"),
+				"big_id":         Number("123456789012345678901234567891"),
+				"ratio":          Number("1e3"),
+				"port_numbers":   []interface{}{Number("1000")},
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParsePlanFromFile("test/numbers.stdout", tc.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got.ResourceChanges) != 1 {
+				t.Fatalf("Expected 1 resource change but got %d", len(got.ResourceChanges))
+			}
+
+			if diff := cmp.Diff(got.ResourceChanges[0].GetAfterResource(), tc.expected); diff != "" {
+				t.Errorf("(-got, +expected)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParsePlanBlocks(t *testing.T) {
+	got, err := ParsePlanFromFile("test/blocks.stdout")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.ResourceChanges) != 1 {
+		t.Fatalf("Expected 1 resource change but got %d", len(got.ResourceChanges))
+	}
+	rc := got.ResourceChanges[0]
+
+	expectedBefore := map[string]interface{}{
+		"id": "default/example",
+		"metadata": []interface{}{
+			map[string]interface{}{
+				"annotations": map[string]interface{}{"example.com/owner": nil},
+				"name":        "example",
+			},
+		},
+		"subject": []interface{}{
+			map[string]interface{}{"api_group": "rbac.authorization.k8s.io", "kind": "User", "name": "admin"},
+			map[string]interface{}{"api_group": "rbac.authorization.k8s.io", "kind": "User", "name": "old-user"},
+		},
+	}
+	if diff := cmp.Diff(rc.GetBeforeResource(), expectedBefore); diff != "" {
+		t.Errorf("(-got, +expected)\n%s", diff)
+	}
+
+	expectedAfter := map[string]interface{}{
+		"id": "default/example",
+		"metadata": []interface{}{
+			map[string]interface{}{
+				"annotations": map[string]interface{}{"example.com/owner": "team"},
+				"name":        "example",
+			},
+		},
+		"subject": []interface{}{
+			map[string]interface{}{"api_group": "rbac.authorization.k8s.io", "kind": "User", "name": "admin"},
+			map[string]interface{}{"api_group": "rbac.authorization.k8s.io", "kind": "User", "name": "new-user"},
+		},
+	}
+	if diff := cmp.Diff(rc.GetAfterResource(), expectedAfter); diff != "" {
+		t.Errorf("(-got, +expected)\n%s", diff)
+	}
+
+	subject, err := rc.Attribute("subject[1].name")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if subject.GetAfter() != "new-user" {
+		t.Errorf("Expected: new-user but got %v", subject.GetAfter())
+	}
+	if _, err := rc.Attribute("subject[2]"); err == nil {
+		t.Errorf("Expected an error but didn't get one")
+	}
+}
+
+func TestParsePlanComputedAndSensitive(t *testing.T) {
+	got, err := ParsePlanFromFile("test/computed.stdout")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.ResourceChanges) != 1 {
+		t.Fatalf("Expected 1 resource change but got %d", len(got.ResourceChanges))
+	}
+	rc := got.ResourceChanges[0]
+
+	cases := map[string]struct {
+		path      string
+		computed  bool
+		sensitive bool
+		after     interface{}
+	}{
+		"value": {
+			path:  "id",
+			after: "i-0123456789abcdef0",
+		},
+		"computed value": {
+			path:     "ipv6_addresses",
+			computed: true,
+			after:    UnknownMarker,
+		},
+		"array of computed values": {
+			path:     "private_ips",
+			computed: true,
+			after:    []interface{}{UnknownMarker},
+		},
+		"computed array": {
+			path:     "secondary_private_ips",
+			computed: true,
+			after:    UnknownMarker,
+		},
+		"computed map": {
+			path:     "tags",
+			computed: true,
+			after:    UnknownMarker,
+		},
+		"array of sensitive values": {
+			path:      "passwords",
+			sensitive: true,
+			after:     []interface{}{SensitiveMarker},
+		},
+		"computed heredoc": {
+			path:     "user_data",
+			computed: true,
+			after:    UnknownMarker,
+		},
+		"sensitive heredoc": {
+			path:      "startup_script",
+			sensitive: true,
+			after:     SensitiveMarker,
+		},
+		"sensitive block": {
+			path:      "credit_specification",
+			sensitive: true,
+			after:     SensitiveMarker,
+		},
+		"block": {
+			path:  "root_block_device",
+			after: map[string]interface{}{"volume_size": 8},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			a, err := rc.Attribute(tc.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if a.IsComputed() != tc.computed {
+				t.Errorf("Expected computed: %v but got %v", tc.computed, a.IsComputed())
+			}
+			if a.IsSensitive() != tc.sensitive {
+				t.Errorf("Expected sensitive: %v but got %v", tc.sensitive, a.IsSensitive())
+			}
+			if diff := cmp.Diff(a.GetAfter(), tc.after); diff != "" {
+				t.Errorf("(-got, +expected)\n%s", diff)
+			}
+		})
+	}
+
+	expected := map[string]interface{}{
+		"id": "i-0123456789abcdef0",
+		"root_block_device": []interface{}{
+			map[string]interface{}{"volume_size": 8},
+		},
+	}
+	if diff := cmp.Diff(rc.GetAfterResource(IgnoreComputed, IgnoreSensitive), expected); diff != "" {
+		t.Errorf("(-got, +expected)\n%s", diff)
+	}
+}
+
+func TestParsePlanSensitivity(t *testing.T) {
+	got, err := ParsePlanFromFile("test/sensitivity.stdout")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.ResourceChanges) != 1 {
+		t.Fatalf("Expected 1 resource change but got %d", len(got.ResourceChanges))
+	}
+
+	expected := []*AttributeDiff{
+		&AttributeDiff{Path: Path{"username"}, Before: SensitiveMarker, After: SensitiveMarker, UpdateType: UpdateInPlaceResource, Sensitive: true, AfterSensitive: true},
+		&AttributeDiff{Path: Path{"port"}, Before: SensitiveMarker, After: SensitiveMarker, UpdateType: UpdateInPlaceResource, Sensitive: true, BeforeSensitive: true},
+		&AttributeDiff{Path: Path{"secrets", 0}, After: SensitiveMarker, UpdateType: NewResource, Sensitive: true, AfterSensitive: true},
+		&AttributeDiff{Path: Path{"users", 0, "name"}, Before: "admin", After: "root", UpdateType: UpdateInPlaceResource},
+		&AttributeDiff{Path: Path{"token"}, After: SensitiveMarker, UpdateType: NewResource, Sensitive: true, AfterSensitive: true},
+		&AttributeDiff{Path: Path{"restore_to_point_in_time", 0}, Before: SensitiveMarker, After: SensitiveMarker, UpdateType: UpdateInPlaceResource, Sensitive: true, AfterSensitive: true},
+	}
+	if diff := cmp.Diff(got.ResourceChanges[0].Diff(), expected); diff != "" {
+		t.Errorf("(-got, +expected)\n%s", diff)
+	}
+
+	expectedAfter := map[string]interface{}{
+		"id": "main",
+	}
+	if diff := cmp.Diff(got.ResourceChanges[0].GetAfterResource(IgnoreSensitive), expectedAfter); diff != "" {
+		t.Errorf("(-got, +expected)\n%s", diff)
+	}
+
+	// warnings also apply to the elements of arrays
+	cases := map[string]struct {
+		path            string
+		beforeSensitive bool
+		afterSensitive  bool
+	}{
+		"object element becomes sensitive": {
+			path:           "users[0]",
+			afterSensitive: true,
+		},
+		"element is no longer sensitive": {
+			path:            "users[1]",
+			beforeSensitive: true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			a, err := got.ResourceChanges[0].Attribute(tc.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			before, after := getSensitivity(a)
+			if before != tc.beforeSensitive || after != tc.afterSensitive {
+				t.Errorf("Expected sensitivity: %v, %v but got %v, %v", tc.beforeSensitive, tc.afterSensitive, before, after)
+			}
+			if !a.IsSensitive() {
+				t.Errorf("Expected the element to be sensitive")
+			}
+		})
+	}
+}
+
+func TestParsePlanHeredoc(t *testing.T) {
+	got, err := ParsePlanFromFile("test/heredoc.stdout")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.ResourceChanges) != 2 {
+		t.Fatalf("Expected 2 resource changes but got %d", len(got.ResourceChanges))
+	}
+
+	cases := map[string]struct {
+		rc             *ResourceChange
+		path           string
+		expectedBefore string
+		expectedAfter  string
+		expectedLines  []HeredocLine
+	}{
+		"heredoc changed": {
+			rc:             got.ResourceChanges[0],
+			path:           "manifest",
+			expectedBefore: "apiVersion: apps/v1\nkind: Deployment\nspec:\n  replicas: 1\n  template:\n    metadata:\n      labels:\n        app: web",
+			expectedAfter:  "apiVersion: apps/v1\nkind: Deployment\nspec:\n  replicas: 3\n  template:\n    metadata:\n      labels:\n        app: web",
+			expectedLines: []HeredocLine{
+				{Text: "apiVersion: apps/v1", UpdateType: NoOpResource},
+				{Text: "kind: Deployment", UpdateType: NoOpResource},
+				{Text: "spec:", UpdateType: NoOpResource},
+				{Text: "  replicas: 1", UpdateType: DestroyResource},
+				{Text: "  replicas: 3", UpdateType: NewResource},
+				{Text: "  template:", UpdateType: NoOpResource},
+				{Text: "    metadata:", UpdateType: NoOpResource},
+				{Text: "      labels:", UpdateType: NoOpResource},
+				{Text: "        app: web", UpdateType: NoOpResource},
+			},
+		},
+		"heredoc created": {
+			rc:             got.ResourceChanges[1],
+			path:           "user_data",
+			expectedBefore: "",
+			expectedAfter:  "#!/bin/bash\nfor i in 1 2; do\n  echo $i\ndone",
+			expectedLines: []HeredocLine{
+				{Text: "#!/bin/bash", UpdateType: NewResource},
+				{Text: "for i in 1 2; do", UpdateType: NewResource},
+				{Text: "  echo $i", UpdateType: NewResource},
+				{Text: "done", UpdateType: NewResource},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			a, err := tc.rc.Attribute(tc.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			h, ok := a.(*HeredocAttributeChange)
+			if !ok {
+				t.Fatalf("Expected a *HeredocAttributeChange but got %T", a)
+			}
+
+			if got := h.GetBefore(); got != tc.expectedBefore {
+				t.Errorf("Expected before: %q but got %q", tc.expectedBefore, got)
+			}
+			if got := h.GetAfter(); got != tc.expectedAfter {
+				t.Errorf("Expected after: %q but got %q", tc.expectedAfter, got)
+			}
+			if diff := cmp.Diff(h.Lines(), tc.expectedLines); diff != "" {
+				t.Errorf("(-got, +expected)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParsePlanSensitiveHeredoc(t *testing.T) {
+	input := `
+Terraform will perform the following actions:
+
+  # aws_instance.web will be updated in-place
+  ~ resource "aws_instance" "web" {
+      ~ startup_script = <<~EOT
+            echo secret
+        EOT -> (sensitive)
+      ~ instance_type  = "t2.micro" -> "t3.micro"
+    }
+
+Plan: 0 to add, 1 to change, 0 to destroy.
+`
+	got, err := ParsePlan(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.ResourceChanges) != 1 {
+		t.Fatalf("Expected 1 resource change but got %d", len(got.ResourceChanges))
+	}
+	rc := got.ResourceChanges[0]
+
+	a, err := rc.Attribute("startup_script")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !a.IsSensitive() {
+		t.Errorf("Expected the heredoc to be sensitive")
+	}
+	if a.GetAfter() != SensitiveMarker {
+		t.Errorf("Expected: %v but got %v", SensitiveMarker, a.GetAfter())
+	}
+	if _, err := rc.Attribute("instance_type"); err != nil {
+		t.Errorf("Expected the attribute after the heredoc to be parsed: %s", err)
+	}
+}
+
+func TestParsePlanJSONEncode(t *testing.T) {
+	got, err := ParsePlanFromFile("test/jsonencode_shapes.stdout")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.ResourceChanges) != 5 {
+		t.Fatalf("Expected 5 resource changes but got %d", len(got.ResourceChanges))
+	}
+
+	cases := map[string]struct {
+		rc                 *ResourceChange
+		path               string
+		expectedBefore     interface{}
+		expectedAfter      interface{}
+		expectedBeforeJSON string
+		expectedAfterJSON  string
+	}{
+		"object changed": {
+			rc:   got.ResourceChanges[0],
+			path: "policy",
+			expectedBefore: map[string]interface{}{
+				"Statement": []interface{}{
+					map[string]interface{}{"Action": []interface{}{"s3:GetObject"}},
+				},
+			},
+			expectedAfter: map[string]interface{}{
+				"Statement": []interface{}{
+					map[string]interface{}{"Action": []interface{}{"s3:*"}},
+					map[string]interface{}{"Action": "sqs:SendMessage", "Effect": "Allow", "Resource": "*"},
+				},
+			},
+			expectedBeforeJSON: `{"Statement":[{"Action":["s3:GetObject"]}]}`,
+			expectedAfterJSON:  `{"Statement":[{"Action":["s3:*"]},{"Action":"sqs:SendMessage","Effect":"Allow","Resource":"*"}]}`,
+		},
+		"array changed": {
+			rc:                 got.ResourceChanges[1],
+			path:               "value",
+			expectedBefore:     []interface{}{"a", "b", "d"},
+			expectedAfter:      []interface{}{"a", "c", "e"},
+			expectedBeforeJSON: `["a","b","d"]`,
+			expectedAfterJSON:  `["a","c","e"]`,
+		},
+		"scalar changed": {
+			rc:                 got.ResourceChanges[2],
+			path:               "value",
+			expectedBefore:     "old",
+			expectedAfter:      "new",
+			expectedBeforeJSON: `"old"`,
+			expectedAfterJSON:  `"new"`,
+		},
+		"one line scalar created": {
+			rc:                 got.ResourceChanges[2],
+			path:               "string",
+			expectedBefore:     nil,
+			expectedAfter:      "x",
+			expectedBeforeJSON: "null",
+			expectedAfterJSON:  `"x"`,
+		},
+		"one line empty object created": {
+			rc:                 got.ResourceChanges[2],
+			path:               "empty",
+			expectedBefore:     nil,
+			expectedAfter:      map[string]interface{}{},
+			expectedBeforeJSON: "null",
+			expectedAfterJSON:  "{}",
+		},
+		"one line number changed": {
+			rc:                 got.ResourceChanges[2],
+			path:               "count",
+			expectedBefore:     1,
+			expectedAfter:      2,
+			expectedBeforeJSON: "1",
+			expectedAfterJSON:  "2",
+		},
+		"one line empty array deleted": {
+			rc:                 got.ResourceChanges[2],
+			path:               "removed",
+			expectedBefore:     []interface{}{},
+			expectedAfter:      nil,
+			expectedBeforeJSON: "[]",
+			expectedAfterJSON:  "null",
+		},
+		"nested jsonencode": {
+			rc:                 got.ResourceChanges[3],
+			path:               "definition.Config",
+			expectedBefore:     nil,
+			expectedAfter:      map[string]interface{}{"retries": 3},
+			expectedBeforeJSON: "null",
+			expectedAfterJSON:  `{"retries":3}`,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			a, err := tc.rc.Attribute(tc.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			j, ok := a.(*JSONEncodeAttributeChange)
+			if !ok {
+				t.Fatalf("Expected a *JSONEncodeAttributeChange but got %T", a)
+			}
+
+			if diff := cmp.Diff(j.GetBefore(), tc.expectedBefore); diff != "" {
+				t.Errorf("before (-got, +expected)\n%s", diff)
+			}
+			if diff := cmp.Diff(j.GetAfter(), tc.expectedAfter); diff != "" {
+				t.Errorf("after (-got, +expected)\n%s", diff)
+			}
+
+			beforeJSON, err := j.GetBeforeJSON()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if beforeJSON != tc.expectedBeforeJSON {
+				t.Errorf("Expected before JSON: %s but got %s", tc.expectedBeforeJSON, beforeJSON)
+			}
+			afterJSON, err := j.GetAfterJSON()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if afterJSON != tc.expectedAfterJSON {
+				t.Errorf("Expected after JSON: %s but got %s", tc.expectedAfterJSON, afterJSON)
+			}
+		})
+	}
+}
+
+func TestParsePlanJSONEncodeComputedAndSensitive(t *testing.T) {
+	got, err := ParsePlanFromFile("test/jsonencode_shapes.stdout")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rc := got.ResourceChanges[4]
+
+	cases := map[string]struct {
+		path           string
+		expectedBefore interface{}
+		expectedAfter  interface{}
+		computed       bool
+		sensitive      bool
+	}{
+		"sensitive": {
+			path:           "secret",
+			expectedBefore: map[string]interface{}{"token": "abc"},
+			expectedAfter:  SensitiveMarker,
+			sensitive:      true,
+		},
+		"computed": {
+			path:           "value",
+			expectedBefore: map[string]interface{}{"retries": 3},
+			expectedAfter:  UnknownMarker,
+			computed:       true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			a, err := rc.Attribute(tc.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(a.GetBefore(), tc.expectedBefore); diff != "" {
+				t.Errorf("before (-got, +expected)\n%s", diff)
+			}
+			if diff := cmp.Diff(a.GetAfter(), tc.expectedAfter); diff != "" {
+				t.Errorf("after (-got, +expected)\n%s", diff)
+			}
+			if a.IsComputed() != tc.computed {
+				t.Errorf("Expected computed: %v but got %v", tc.computed, a.IsComputed())
+			}
+			if a.IsSensitive() != tc.sensitive {
+				t.Errorf("Expected sensitive: %v but got %v", tc.sensitive, a.IsSensitive())
+			}
+			if _, err := a.(*JSONEncodeAttributeChange).GetAfterJSON(); err == nil {
+				t.Errorf("Expected an error encoding the value after the change but didn't get one")
+			}
+		})
+	}
+
+	expectedDiff := []*AttributeDiff{
+		&AttributeDiff{Path: Path{"secret"}, Before: map[string]interface{}{"token": "abc"}, After: SensitiveMarker, UpdateType: UpdateInPlaceResource, Sensitive: true, AfterSensitive: true},
+		&AttributeDiff{Path: Path{"value"}, Before: map[string]interface{}{"retries": 3}, After: UnknownMarker, UpdateType: UpdateInPlaceResource, Computed: true},
+	}
+	if diff := cmp.Diff(rc.Diff(), expectedDiff); diff != "" {
+		t.Errorf("(-got, +expected)\n%s", diff)
+	}
+}
+
+func TestParsePlanUnterminatedJSONEncode(t *testing.T) {
+	input := `
+Terraform will perform the following actions:
+
+  # aws_ssm_parameter.value will be updated in-place
+  ~ resource "aws_ssm_parameter" "value" {
+      ~ value = jsonencode(
+          ~ "old" -> "new"
+    }
+
+Plan: 0 to add, 1 to change, 0 to destroy.
+`
+	_, err := ParsePlan(strings.NewReader(input))
+	if err == nil {
+		t.Fatalf("Expected an error but didn't get one")
+	}
+	if expected := "unexpected line while parsing jsonencode attribute: }"; err.Error() != expected {
+		t.Errorf("Expected: %s but got %s", expected, err)
+	}
+}
+
+func TestJSONEncodeUnmarshal(t *testing.T) {
+	got, err := ParsePlanFromFile("test/jsonencode_shapes.stdout")
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := got.ResourceChanges[3].Attribute("definition")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type definition struct {
+		Comment string
+		Config  struct {
+			Retries int `json:"retries"`
+		}
+		States []string
+	}
+	var after definition
+	if err := a.(*JSONEncodeAttributeChange).UnmarshalAfter(&after); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := definition{
+		Comment: "example",
+		States:  []string{"Start", "End"},
+	}
+	expected.Config.Retries = 3
+	if diff := cmp.Diff(after, expected); diff != "" {
+		t.Errorf("(-got, +expected)\n%s", diff)
+	}
+
+	var before *definition
+	if err := a.(*JSONEncodeAttributeChange).UnmarshalBefore(&before); err != nil {
+		t.Fatal(err)
+	}
+	if before != nil {
+		t.Errorf("Expected the value before the resource is created to be nil but got %v", before)
+	}
+
+	computed := &JSONEncodeAttributeChange{
+		Name: "computed",
+		AttributeChanges: []Attr{
+			&AttributeChange{OldValue: "old", NewValue: UnknownMarker, UpdateType: UpdateInPlaceResource},
+		},
+		UpdateType: UpdateInPlaceResource,
+	}
+	if _, err := computed.GetAfterJSON(); err == nil {
+		t.Errorf("Expected an error encoding a computed value but didn't get one")
+	}
+	if err := computed.UnmarshalAfter(&after); err == nil {
+		t.Errorf("Expected an error decoding a computed value but didn't get one")
+	}
+}
+
+func TestParsePlanUnknownResourceComment(t *testing.T) {
+	if _, err := ParsePlanFromFile("test/unknown_action.stdout"); err == nil {
+		t.Fatalf("Expected an error but didn't get one")
+	}
+
+	// comments that are not followed by a resource change line are not resources
+	input := `
+Terraform will perform the following actions:
+
+  # aws_instance.web will be updated in-place
+  ~ resource "aws_instance" "web" {
+      ~ instance_type = "t2.micro" -> "t3.micro"
+    }
+
+  # a comment that is not a resource
+  # (with an annotation)
+
+Plan: 0 to add, 1 to change, 0 to destroy.
+`
+	got, err := ParsePlan(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.ResourceChanges) != 1 {
+		t.Errorf("Expected 1 resource change but got %d", len(got.ResourceChanges))
+	}
+	if got.Summary == nil {
+		t.Errorf("Expected the summary after the comment to be parsed")
+	}
+}
@@ -0,0 +1,247 @@
+package tfplanparse
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var identifierRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+// Path is the path to a nested attribute
+// Each step is either a string containing the name of an attribute or a map key, or an int containing the index of an array element
+type Path []interface{}
+
+// ParsePath creates a Path from an attribute path in the same format as terraform
+// Names are delimited with ".", array elements are referred to with [n], and map keys with either .key or ["key"]
+// The index of an array element or block is its index in the list after the change, or in the list before the change if it is destroyed
+// Example: metadata.annotations["example.com/name"].rules[0]
+func ParsePath(path string) (Path, error) {
+	result := Path{}
+
+	for i := 0; i < len(path); {
+		if path[i] == '[' {
+			key, n, err := readAddressKey(path[i:])
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse path %s: %s", path, err)
+			}
+			result = append(result, key)
+			i += n
+			continue
+		}
+
+		if len(result) > 0 {
+			if path[i] != '.' {
+				return nil, fmt.Errorf("failed to parse path %s: unexpected %q at position %d", path, path[i], i)
+			}
+			i++
+		}
+		end := strings.IndexAny(path[i:], ".[")
+		if end == -1 {
+			end = len(path) - i
+		}
+		if end == 0 {
+			return nil, fmt.Errorf("failed to parse path %s: expected a name at position %d", path, i)
+		}
+		result = append(result, path[i:i+end])
+		i += end
+	}
+
+	if len(result) == 0 {
+		return nil, fmt.Errorf("failed to parse path: path is empty")
+	}
+
+	return result, nil
+}
+
+// String returns the path in the same format as terraform
+// Example: metadata.annotations["example.com/name"].rules[0]
+func (p Path) String() string {
+	var b strings.Builder
+
+	for i, step := range p {
+		switch s := step.(type) {
+		case int:
+			b.WriteString(fmt.Sprintf("[%d]", s))
+		case string:
+			if !identifierRegexp.MatchString(s) {
+				b.WriteString("[" + strconv.Quote(s) + "]")
+				continue
+			}
+			if i > 0 {
+				b.WriteString(".")
+			}
+			b.WriteString(s)
+		}
+	}
+
+	return b.String()
+}
+
+// child returns a copy of the path with the steps appended
+func (p Path) child(steps ...interface{}) Path {
+	result := make(Path, len(p), len(p)+len(steps))
+	copy(result, p)
+
+	return append(result, steps...)
+}
+
+// childAttrs returns the attributes nested in an attribute
+// The returned bool is true if the nested attributes are referred to by their index instead of their name
+func childAttrs(a Attr) ([]Attr, bool) {
+	switch ac := a.(type) {
+	case *MapAttributeChange:
+		return ac.AttributeChanges, false
+	case *JSONEncodeAttributeChange:
+		// the decoded value is parsed as a single attribute without a name
+		if len(ac.AttributeChanges) == 1 && ac.AttributeChanges[0].GetName() == "" {
+			return childAttrs(ac.AttributeChanges[0])
+		}
+		return ac.AttributeChanges, false
+	case *ArrayAttributeChange:
+		return ac.AttributeChanges, true
+	case *BlockChange:
+		return ac.AttributeChanges, false
+	}
+
+	return nil, false
+}
+
+// childSteps returns the steps from the attribute containing attrs to each of the attributes
+// Blocks are referred to by their name and their index among the blocks with the same name
+func childSteps(attrs []Attr, indexed bool) [][]interface{} {
+	result := make([][]interface{}, 0, len(attrs))
+	elements := &elementIndex{}
+	blocks := map[string]*elementIndex{}
+
+	for _, a := range attrs {
+		switch {
+		case indexed:
+			result = append(result, []interface{}{elements.next(a)})
+		case isBlock(a):
+			if blocks[a.GetName()] == nil {
+				blocks[a.GetName()] = &elementIndex{}
+			}
+			result = append(result, []interface{}{a.GetName(), blocks[a.GetName()].next(a)})
+		default:
+			result = append(result, []interface{}{a.GetName()})
+		}
+	}
+
+	return result
+}
+
+// elementIndex counts the elements of an array, or the blocks with the same name, before and after the change
+type elementIndex struct {
+	before int
+	after  int
+}
+
+// next returns the index of a in the list after the change, or in the list before the change if a is destroyed
+func (e *elementIndex) next(a Attr) int {
+	if a.GetUpdateType() == DestroyResource {
+		e.before++
+		return e.before - 1
+	}
+
+	n := e.after
+	e.after++
+	if a.GetUpdateType() != NewResource {
+		e.before++
+	}
+	return n
+}
+
+// elementAt returns the element of an array, or the block among the blocks with the same name, at index n
+// If a destroyed element and an element that is not destroyed have the same index, the element that is not destroyed is returned
+func elementAt(attrs []Attr, n int) (Attr, bool) {
+	var destroyed Attr
+	e := &elementIndex{}
+	for _, a := range attrs {
+		if e.next(a) != n {
+			continue
+		}
+		if a.GetUpdateType() != DestroyResource {
+			return a, true
+		}
+		if destroyed == nil {
+			destroyed = a
+		}
+	}
+
+	return destroyed, destroyed != nil
+}
+
+// blockIndex returns the step at i if it is an index
+func blockIndex(p Path, i int) (int, bool) {
+	if i >= len(p) {
+		return 0, false
+	}
+	n, ok := p[i].(int)
+	return n, ok
+}
+
+func isBlock(a Attr) bool {
+	_, ok := a.(*BlockChange)
+	return ok
+}
+
+// findAttr returns the attribute at the path, relative to the attributes in attrs
+// The index of a block can be omitted to refer to the first block with the name
+func findAttr(attrs []Attr, indexed bool, path string) (Attr, error) {
+	p, err := ParsePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var result Attr
+	for i := 0; i < len(p); i++ {
+		if result != nil {
+			attrs, indexed = childAttrs(result)
+			if attrs == nil {
+				return nil, fmt.Errorf("failed to find attribute %s: %s has no nested attributes", p, p[:i])
+			}
+		}
+
+		result = nil
+		switch s := p[i].(type) {
+		case int:
+			if !indexed {
+				return nil, fmt.Errorf("failed to find attribute %s: expected a name but got index %d", p, s)
+			}
+			element, ok := elementAt(attrs, s)
+			if !ok {
+				return nil, fmt.Errorf("failed to find attribute %s: index %d is out of range", p, s)
+			}
+			result = element
+		case string:
+			if indexed {
+				return nil, fmt.Errorf("failed to find attribute %s: expected an index but got %s", p, s)
+			}
+
+			matches := []Attr{}
+			for _, a := range attrs {
+				if a.GetName() == s {
+					matches = append(matches, a)
+				}
+			}
+			if len(matches) == 0 {
+				return nil, fmt.Errorf("failed to find attribute %s: %s not found", p, p[:i+1])
+			}
+
+			result = matches[0]
+			if n, ok := blockIndex(p, i+1); ok && isBlock(result) {
+				block, ok := elementAt(matches, n)
+				if !ok {
+					return nil, fmt.Errorf("failed to find attribute %s: index %d is out of range", p, n)
+				}
+				// the index of the block is consumed with the name
+				result = block
+				i++
+			}
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,284 @@
+package tfplanparse
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParsePath(t *testing.T) {
+	cases := map[string]struct {
+		path        string
+		expected    Path
+		shouldError bool
+	}{
+		"empty path": {
+			path:        "",
+			shouldError: true,
+		},
+		"attribute": {
+			path:     "ami",
+			expected: Path{"ami"},
+		},
+		"nested attribute": {
+			path:     "root_block_device.volume_size",
+			expected: Path{"root_block_device", "volume_size"},
+		},
+		"array elements": {
+			path:     "ingress[0].cidr_blocks[1]",
+			expected: Path{"ingress", 0, "cidr_blocks", 1},
+		},
+		"quoted key": {
+			path:     `metadata.annotations["example.com/name"].kind`,
+			expected: Path{"metadata", "annotations", "example.com/name", "kind"},
+		},
+		"starts with an index": {
+			path:     "[0].name",
+			expected: Path{0, "name"},
+		},
+		"starts with a quoted key": {
+			path:     `["encoded"]`,
+			expected: Path{"encoded"},
+		},
+		"trailing delimiter": {
+			path:        "metadata.",
+			shouldError: true,
+		},
+		"missing delimiter": {
+			path:        "ingress[0]name",
+			shouldError: true,
+		},
+		"unterminated key": {
+			path:        `tags["Name`,
+			shouldError: true,
+		},
+		"invalid index": {
+			path:        "ingress[a]",
+			shouldError: true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParsePath(tc.path)
+			if err == nil && tc.shouldError {
+				t.Fatalf("Expected an error but didn't get one")
+			}
+			if err != nil && !tc.shouldError {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Fatalf("Expected: %v but got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestPathString(t *testing.T) {
+	cases := map[string]struct {
+		path     Path
+		expected string
+	}{
+		"empty path": {
+			path:     Path{},
+			expected: "",
+		},
+		"attribute": {
+			path:     Path{"ami"},
+			expected: "ami",
+		},
+		"nested attribute": {
+			path:     Path{"root_block_device", "volume_size"},
+			expected: "root_block_device.volume_size",
+		},
+		"array element": {
+			path:     Path{"ingress", 0, "cidr_blocks", 1},
+			expected: "ingress[0].cidr_blocks[1]",
+		},
+		"key that is not an identifier": {
+			path:     Path{"metadata", "annotations", "example.com/name"},
+			expected: `metadata.annotations["example.com/name"]`,
+		},
+		"key with quotes": {
+			path:     Path{"tags", `"quoted"`},
+			expected: `tags["\"quoted\""]`,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := tc.path.String(); got != tc.expected {
+				t.Errorf("Expected: %v but got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestResourceChangeAttribute(t *testing.T) {
+	got, err := ParsePlanFromFile("test/jsonencode.stdout")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.ResourceChanges) != 1 {
+		t.Fatalf("Expected 1 resource change but got %d", len(got.ResourceChanges))
+	}
+	rc := got.ResourceChanges[0]
+
+	cases := map[string]struct {
+		path           string
+		expectedBefore interface{}
+		expectedAfter  interface{}
+		expectedType   UpdateType
+		shouldError    bool
+	}{
+		"attribute": {
+			path:           "id",
+			expectedBefore: "my-namespace/user_is_view",
+			expectedType:   DestroyResource,
+		},
+		"nested attribute": {
+			path:           "role_ref.kind",
+			expectedBefore: "ClusterRole",
+			expectedType:   DestroyResource,
+		},
+		"jsonencode value": {
+			path:           `metadata.annotations["encoded"].roleRef.kind`,
+			expectedBefore: "ClusterRole",
+			expectedType:   DestroyResource,
+		},
+		"array element in a jsonencode value": {
+			path:           `metadata.annotations.encoded.subjects[0].name`,
+			expectedBefore: "user@email.com",
+			expectedType:   DestroyResource,
+		},
+		"missing attribute": {
+			path:        "metadata.missing",
+			shouldError: true,
+		},
+		"index of a map": {
+			path:        "metadata.labels[0]",
+			shouldError: true,
+		},
+		"name of an array element": {
+			path:        `metadata.annotations.encoded.subjects.name`,
+			shouldError: true,
+		},
+		"index out of range": {
+			path:        `metadata.annotations.encoded.subjects[1]`,
+			shouldError: true,
+		},
+		"nested in a value": {
+			path:        "id.value",
+			shouldError: true,
+		},
+		"invalid path": {
+			path:        "metadata..labels",
+			shouldError: true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			a, err := rc.Attribute(tc.path)
+			if err == nil && tc.shouldError {
+				t.Fatalf("Expected an error but didn't get one")
+			}
+			if err != nil && !tc.shouldError {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if tc.shouldError {
+				return
+			}
+
+			if before := a.GetBefore(); !reflect.DeepEqual(before, tc.expectedBefore) {
+				t.Errorf("Expected before: %v but got %v", tc.expectedBefore, before)
+			}
+			if after := a.GetAfter(); !reflect.DeepEqual(after, tc.expectedAfter) {
+				t.Errorf("Expected after: %v but got %v", tc.expectedAfter, after)
+			}
+			if a.GetUpdateType() != tc.expectedType {
+				t.Errorf("Expected update type: %v but got %v", tc.expectedType, a.GetUpdateType())
+			}
+		})
+	}
+}
+
+func TestMapAndArrayAttribute(t *testing.T) {
+	got, err := ParsePlanFromFile("test/jsonencode.stdout")
+	if err != nil {
+		t.Fatal(err)
+	}
+	encoded, err := got.ResourceChanges[0].Attribute(`metadata.annotations["encoded"]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	metadata, err := got.ResourceChanges[0].Attribute("metadata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	annotations, err := metadata.(*BlockChange).Attribute("annotations")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromMap, err := annotations.(*MapAttributeChange).Attribute(`["encoded"]`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fromMap != encoded {
+		t.Errorf("Expected the attribute relative to the map to be the same as the attribute relative to the resource")
+	}
+
+	subjects, err := got.ResourceChanges[0].Attribute(`metadata.annotations["encoded"].subjects`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kind, err := subjects.(*ArrayAttributeChange).Attribute("[0].kind")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if kind.GetBefore() != "User" {
+		t.Errorf("Expected: User but got %v", kind.GetBefore())
+	}
+	if _, err := subjects.(*ArrayAttributeChange).Attribute("kind"); err == nil {
+		t.Errorf("Expected an error but didn't get one")
+	}
+}
+
+func TestBlockIndex(t *testing.T) {
+	rc := &ResourceChange{
+		AttributeChanges: []Attr{
+			&BlockChange{
+				Name: "ebs",
+				AttributeChanges: []Attr{
+					&AttributeChange{Name: "size", OldValue: 2, UpdateType: DestroyResource},
+				},
+				UpdateType: DestroyResource,
+			},
+			&BlockChange{
+				Name: "ebs",
+				AttributeChanges: []Attr{
+					&AttributeChange{Name: "size", NewValue: 3, UpdateType: NewResource},
+				},
+				UpdateType: NewResource,
+			},
+		},
+	}
+
+	expectedPaths := []string{"ebs[0].size", "ebs[0].size"}
+	paths := []string{}
+	for _, d := range rc.Diff() {
+		paths = append(paths, d.Path.String())
+	}
+	if !reflect.DeepEqual(paths, expectedPaths) {
+		t.Errorf("Expected: %v but got %v", expectedPaths, paths)
+	}
+
+	size, err := rc.Attribute("ebs[0].size")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size.GetAfter() != 3 {
+		t.Errorf("Expected the block after the change but got %v", size.GetAfter())
+	}
+	if _, err := rc.Attribute("ebs[1]"); err == nil {
+		t.Errorf("Expected an error but didn't get one")
+	}
+}
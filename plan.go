@@ -0,0 +1,158 @@
+package tfplanparse
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type Plan struct {
+	// Dialect contains the CLI output format the plan was parsed as
+	// Refer to dialect.go for possible values
+	Dialect Dialect
+
+	// ResourceChanges contains all the planned resource changes
+	ResourceChanges []*ResourceChange
+
+	// DriftChanges contains all the changes terraform detected outside of terraform
+	// These are reported in the "Objects have changed outside of Terraform" section, and are not planned
+	DriftChanges []*ResourceChange
+
+	// OutputChanges contains all the planned output changes
+	OutputChanges []*OutputChange
+
+	// Summary contains the counts from the "Plan: " line, if any
+	// Plans with no changes or only output changes do not have a summary
+	Summary *PlanSummary
+
+	// Diagnostics contains all the errors and warnings in the output
+	Diagnostics []*Diagnostic
+}
+
+type PlanSummary struct {
+	Import  int
+	Add     int
+	Change  int
+	Destroy int
+	Forget  int
+}
+
+var planSummaryCountRegexp = regexp.MustCompile(`(\d+) to (import|add|change|destroy|forget)`)
+
+// IsPlanSummaryLine returns true if the line is the plan summary
+// Example: Plan: 1 to add, 0 to change, 0 to destroy.
+func IsPlanSummaryLine(line string) bool {
+	line = strings.TrimSpace(line)
+	return strings.HasPrefix(line, CHANGES_END_STRING) && planSummaryCountRegexp.MatchString(line)
+}
+
+// NewPlanSummaryFromLine creates a PlanSummary from a valid plan summary line
+func NewPlanSummaryFromLine(line string) (*PlanSummary, error) {
+	line = strings.TrimSpace(line)
+	if !IsPlanSummaryLine(line) {
+		return nil, fmt.Errorf("%s is not a valid line to initialize a PlanSummary", line)
+	}
+
+	result := &PlanSummary{}
+	for _, match := range planSummaryCountRegexp.FindAllStringSubmatch(line, -1) {
+		count, err := strconv.Atoi(match[1])
+		if err != nil {
+			return nil, fmt.Errorf("failed to read plan summary from line %s: %s", line, err)
+		}
+
+		switch match[2] {
+		case "import":
+			result.Import = count
+		case "add":
+			result.Add = count
+		case "change":
+			result.Change = count
+		case "destroy":
+			result.Destroy = count
+		case "forget":
+			result.Forget = count
+		}
+	}
+
+	return result, nil
+}
+
+// String returns the summary in the same format as terraform
+func (s PlanSummary) String() string {
+	result := fmt.Sprintf("%d to add, %d to change, %d to destroy", s.Add, s.Change, s.Destroy)
+	if s.Import > 0 {
+		result = fmt.Sprintf("%d to import, %s", s.Import, result)
+	}
+	if s.Forget > 0 {
+		result = fmt.Sprintf("%s, %d to forget", result, s.Forget)
+	}
+	return result
+}
+
+// Errors returns the diagnostics with an error severity
+func (p *Plan) Errors() []*Diagnostic {
+	result := []*Diagnostic{}
+	for _, d := range p.Diagnostics {
+		if d.IsError() {
+			result = append(result, d)
+		}
+	}
+
+	return result
+}
+
+// Warnings returns the diagnostics with a warning severity
+func (p *Plan) Warnings() []*Diagnostic {
+	result := []*Diagnostic{}
+	for _, d := range p.Diagnostics {
+		if d.IsWarning() {
+			result = append(result, d)
+		}
+	}
+
+	return result
+}
+
+// Validate checks the summary against the parsed resource changes
+// A mismatch indicates the output was truncated or contains changes the parser does not understand
+func (p *Plan) Validate() error {
+	if p.Summary == nil {
+		if len(p.ResourceChanges) > 0 {
+			return fmt.Errorf("plan has %d resource changes but no summary", len(p.ResourceChanges))
+		}
+		return nil
+	}
+
+	parsed := p.summarizeResourceChanges()
+	if parsed != *p.Summary {
+		return fmt.Errorf("plan summary %q does not match the parsed resource changes %q", p.Summary, parsed)
+	}
+
+	return nil
+}
+
+func (p *Plan) summarizeResourceChanges() PlanSummary {
+	result := PlanSummary{}
+	for _, rc := range p.ResourceChanges {
+		if rc.IsImported() {
+			result.Import++
+		}
+
+		switch rc.UpdateType {
+		case NewResource:
+			result.Add++
+		case UpdateInPlaceResource:
+			result.Change++
+		case DestroyResource:
+			result.Destroy++
+		case ForceReplaceResource:
+			result.Add++
+			result.Destroy++
+		case ForgetResource:
+			result.Forget++
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,199 @@
+package tfplanparse
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNewPlanSummaryFromLine(t *testing.T) {
+	cases := map[string]struct {
+		line        string
+		expected    *PlanSummary
+		shouldError bool
+	}{
+		"empty line": {
+			line:        "",
+			shouldError: true,
+			expected:    nil,
+		},
+		"summary": {
+			line:        "Plan: 1 to add, 2 to change, 3 to destroy.",
+			shouldError: false,
+			expected: &PlanSummary{
+				Add:     1,
+				Change:  2,
+				Destroy: 3,
+			},
+		},
+		"summary with imports": {
+			line:        "Plan: 4 to import, 1 to add, 2 to change, 3 to destroy.",
+			shouldError: false,
+			expected: &PlanSummary{
+				Import:  4,
+				Add:     1,
+				Change:  2,
+				Destroy: 3,
+			},
+		},
+		"summary with forgets": {
+			line:        "Plan: 0 to add, 0 to change, 0 to destroy, 2 to forget.",
+			shouldError: false,
+			expected: &PlanSummary{
+				Forget: 2,
+			},
+		},
+		"padded with spaces": {
+			line:        "   Plan: 0 to add, 0 to change, 1 to destroy.   ",
+			shouldError: false,
+			expected: &PlanSummary{
+				Destroy: 1,
+			},
+		},
+		"other line": {
+			line:        "Plan: something else",
+			shouldError: true,
+			expected:    nil,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := NewPlanSummaryFromLine(tc.line)
+			if err == nil && tc.shouldError {
+				t.Fatalf("Expected an error but didn't get one")
+			}
+
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Fatalf("Expected: %v but got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestPlanValidate(t *testing.T) {
+	cases := map[string]struct {
+		plan        *Plan
+		shouldError bool
+	}{
+		"empty plan": {
+			plan:        &Plan{},
+			shouldError: false,
+		},
+		"matching summary": {
+			plan: &Plan{
+				ResourceChanges: []*ResourceChange{
+					&ResourceChange{UpdateType: NewResource},
+					&ResourceChange{UpdateType: UpdateInPlaceResource},
+					&ResourceChange{UpdateType: DestroyResource},
+					&ResourceChange{UpdateType: ReadResource},
+				},
+				Summary: &PlanSummary{
+					Add:     1,
+					Change:  1,
+					Destroy: 1,
+				},
+			},
+			shouldError: false,
+		},
+		"replaced resources count as an add and a destroy": {
+			plan: &Plan{
+				ResourceChanges: []*ResourceChange{
+					&ResourceChange{UpdateType: ForceReplaceResource},
+				},
+				Summary: &PlanSummary{
+					Add:     1,
+					Destroy: 1,
+				},
+			},
+			shouldError: false,
+		},
+		"imported resources count as an import": {
+			plan: &Plan{
+				ResourceChanges: []*ResourceChange{
+					&ResourceChange{UpdateType: ImportResource},
+					&ResourceChange{UpdateType: UpdateInPlaceResource, ImportID: "id"},
+					&ResourceChange{UpdateType: ForgetResource},
+					&ResourceChange{UpdateType: MoveResource},
+				},
+				Summary: &PlanSummary{
+					Import: 2,
+					Change: 1,
+					Forget: 1,
+				},
+			},
+			shouldError: false,
+		},
+		"mismatched summary": {
+			plan: &Plan{
+				ResourceChanges: []*ResourceChange{
+					&ResourceChange{UpdateType: NewResource},
+				},
+				Summary: &PlanSummary{
+					Add:     2,
+					Destroy: 1,
+				},
+			},
+			shouldError: true,
+		},
+		"missing summary": {
+			plan: &Plan{
+				ResourceChanges: []*ResourceChange{
+					&ResourceChange{UpdateType: NewResource},
+				},
+			},
+			shouldError: true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := tc.plan.Validate()
+			if err == nil && tc.shouldError {
+				t.Fatalf("Expected an error but didn't get one")
+			}
+			if err != nil && !tc.shouldError {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+		})
+	}
+}
+
+func TestParsedPlansValidate(t *testing.T) {
+	files := []string{
+		"test/anothermap.stdout",
+		"test/array.stdout",
+		"test/blocks.stdout",
+		"test/collections.stdout",
+		"test/computed.stdout",
+		"test/datasources.stdout",
+		"test/deposed.stdout",
+		"test/drift.stdout",
+		"test/forcesreplacement.stdout",
+		"test/heredoc.stdout",
+		"test/hidden.stdout",
+		"test/jsonencode.stdout",
+		"test/jsonencode_shapes.stdout",
+		"test/moved.stdout",
+		"test/nestedmap.stdout",
+		"test/nochanges.stdout",
+		"test/nochanges_v1.stdout",
+		"test/numbers.stdout",
+		"test/outputs.stdout",
+		"test/outputsonly.stdout",
+		"test/reasons.stdout",
+		"test/replace.stdout",
+		"test/resources.stdout",
+		"test/sensitivity.stdout",
+		"test/v1.stdout",
+		"test/warning_v1.stdout",
+	}
+	for _, file := range files {
+		t.Run(file, func(t *testing.T) {
+			plan, err := ParsePlanFromFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := plan.Validate(); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
@@ -2,7 +2,7 @@ package tfplanparse
 
 import (
 	"fmt"
-	"strconv"
+	"regexp"
 	"strings"
 )
 
@@ -13,16 +13,33 @@ const (
 	RESOURCE_UPDATED_IN_PLACE          = " will be updated in-place"
 	RESOURCE_TAINTED                   = " is tainted, so must be replaced"
 	RESOURCE_REPLACED                  = " must be replaced"
+	RESOURCE_REPLACED_BY_REQUEST       = " will be replaced, as requested"
+	RESOURCE_REPLACED_BY_TRIGGERS      = " will be replaced due to changes in replace_triggered_by"
 	RESOURCE_DESTROYED                 = " will be destroyed"
+	RESOURCE_CHANGED_OUTSIDE           = " has changed"
+	RESOURCE_DELETED_OUTSIDE           = " has been deleted"
+	RESOURCE_MOVED                     = " has moved to "
+	RESOURCE_IMPORTED                  = " will be imported"
+	RESOURCE_FORGOTTEN                 = " will no longer be managed by Terraform"
+	RESOURCE_FORGOTTEN_OPENTOFU        = " will no longer be managed by OpenTofu"
+	RESOURCE_MOVED_FROM                = "# (moved from "
+	RESOURCE_IMPORTED_FROM             = "# (imported from "
 )
 
+var resourceDeposedRegexp = regexp.MustCompile(`^(.+) \(deposed object ([^)]+)\)$`)
+
 type ResourceChange struct {
 	// Address contains the absolute resource address
 	Address string
 
 	// ModuleAddress contains the module portion of the absolute address, if any
+	// Example: module.a["eu-west-1"].module.b.aws_s3_bucket.x -> `module.a["eu-west-1"].module.b`
 	ModuleAddress string
 
+	// Mode is either managed or data
+	// Example: data.gcp_compute_image.foo -> "data"
+	Mode ResourceMode
+
 	// The type of the resource
 	// Example: gcp_instance.foo -> "gcp_instance"
 	Type string
@@ -39,19 +56,85 @@ type ResourceChange struct {
 	// Refer to updatetype.go for possible values
 	UpdateType UpdateType
 
+	// DeposedKey contains the key of the deposed object, if the change is for a deposed object
+	// Deposed objects are left over when creating the replacement of a create_before_destroy resource succeeds, but destroying the original fails
+	// Example: # aws_instance.web (deposed object 1a2b3c4d) will be destroyed -> "1a2b3c4d"
+	DeposedKey string
+
 	// Tainted indicates whether the resource is tainted or not
 	Tainted bool
 
+	// ActionReason contains the reason terraform gave for the UpdateType, if any
+	// Refer to actionreason.go for possible values
+	ActionReason ActionReason
+
+	// ActionReasonText contains the text of the reason annotation, if any
+	// Example: # (because aws_instance.a is not in configuration) -> "because aws_instance.a is not in configuration"
+	ActionReasonText string
+
+	// ReplacementOrder indicates whether a replaced resource is destroyed before its replacement is created, or after
+	// It is empty unless UpdateType is ForceReplaceResource
+	ReplacementOrder ReplacementOrder
+
+	// PreviousAddress contains the address the resource was moved from, if any
+	PreviousAddress string
+
+	// ImportID contains the ID the resource is imported from when it is imported and changed
+	// Resources that are only imported have an UpdateType of ImportResource and no ImportID
+	ImportID string
+
 	// AttributeChanges contains all the planned attribute changes
-	AttributeChanges []attributeChange
+	AttributeChanges []Attr
+
+	// Hidden contains the number of unchanged attributes and blocks omitted from the plan output
+	Hidden HiddenCount
+}
+
+// resourceCommentSuffixes contains all the suffixes describing a change to a resource
+var resourceCommentSuffixes = []string{
+	RESOURCE_CREATED,
+	RESOURCE_READ,
+	RESOURCE_UPDATED_IN_PLACE,
+	RESOURCE_TAINTED,
+	RESOURCE_REPLACED,
+	RESOURCE_REPLACED_BY_REQUEST,
+	RESOURCE_REPLACED_BY_TRIGGERS,
+	RESOURCE_DESTROYED,
+	RESOURCE_CHANGED_OUTSIDE,
+	RESOURCE_DELETED_OUTSIDE,
+	RESOURCE_IMPORTED,
+	RESOURCE_FORGOTTEN,
+	RESOURCE_FORGOTTEN_OPENTOFU,
 }
 
 // IsResourceCommentLine returns true if the line is a valid resource comment line
 // A valid line starts with a "#" and has a suffix describing the change
+// Other comments, such as "# (config refers to values not yet known)" or "# (3 unchanged attributes hidden)", are not resource comment lines
 // Example: # module.type.item will be created
 func IsResourceCommentLine(line string) bool {
 	trimmed := strings.TrimSpace(line)
-	return strings.HasPrefix(trimmed, "#") && !strings.HasSuffix(trimmed, RESOURCE_READ_VALUES_NOT_YET_KNOWN)
+	if !strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, "# (") {
+		return false
+	}
+
+	if strings.Contains(trimmed, RESOURCE_MOVED) {
+		return true
+	}
+
+	for _, suffix := range resourceCommentSuffixes {
+		if strings.HasSuffix(trimmed, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// isUnknownResourceCommentLine returns true if the line is a comment that is not a resource comment or annotation line
+// If it is followed by a resource change line, it describes a change to a resource the parser does not recognize
+// Example: # aws_instance.web will be frobnicated
+func isUnknownResourceCommentLine(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return strings.HasPrefix(trimmed, "# ") && !strings.HasPrefix(trimmed, "# (") && !IsResourceCommentLine(trimmed)
 }
 
 // IsResourceTerminator returns true if the line is a "}"
@@ -59,12 +142,27 @@ func IsResourceTerminator(line string) bool {
 	return strings.TrimSpace(line) == "}"
 }
 
+var resourceChangeLineRegexp = regexp.MustCompile(`^([-+/~<=.]*)\s*(resource|data) "[^"]*" "[^"]*"\s+\{$`)
+
+// resourceChangeSymbols contains the symbols allowed on the resource change line for each UpdateType
+var resourceChangeSymbols = map[UpdateType][]string{
+	NewResource:            {"+"},
+	ReadResource:           {"<="},
+	UpdateInPlaceResource:  {"~"},
+	ForceReplaceResource:   {"-/+", "+/-"},
+	DestroyResource:        {"-"},
+	ChangedOutsideResource: {"~"},
+	DeletedOutsideResource: {"-"},
+	MoveResource:           {""},
+	ImportResource:         {""},
+	ForgetResource:         {"."},
+}
+
 // IsResourceChangeLine returns true if the line is a valid resource change line
 // A valid line starts with the change type, then "resource" or "data", and then the type and name, followed by a {
 // Example: + resource "type" "name" {
 func IsResourceChangeLine(line string) bool {
-	line = strings.TrimLeft(line, "+/-~<= ")
-	return (strings.HasPrefix(line, "resource") || strings.HasPrefix(line, "data")) && strings.HasSuffix(line, " {")
+	return resourceChangeLineRegexp.MatchString(strings.TrimSpace(line))
 }
 
 // NewResourceChangeFromComment creates a ResourceChange from a valid resource comment line
@@ -76,7 +174,15 @@ func NewResourceChangeFromComment(comment string) (*ResourceChange, error) {
 		return nil, fmt.Errorf("%s is not a valid line to initialize a resource", comment)
 	}
 
-	if strings.HasSuffix(comment, RESOURCE_CREATED) {
+	if strings.Contains(comment, RESOURCE_MOVED) {
+		addresses := strings.SplitN(strings.TrimPrefix(comment, "# "), RESOURCE_MOVED, 2)
+
+		rc = &ResourceChange{
+			Address:         strings.TrimSpace(addresses[1]),
+			PreviousAddress: strings.TrimSpace(addresses[0]),
+			UpdateType:      MoveResource,
+		}
+	} else if strings.HasSuffix(comment, RESOURCE_CREATED) {
 		resourceAddress = parseResourceAddressFromComment(comment, RESOURCE_CREATED)
 
 		rc = &ResourceChange{
@@ -101,16 +207,34 @@ func NewResourceChangeFromComment(comment string) (*ResourceChange, error) {
 		resourceAddress = parseResourceAddressFromComment(comment, RESOURCE_TAINTED)
 
 		rc = &ResourceChange{
-			Address:    resourceAddress,
-			UpdateType: ForceReplaceResource,
-			Tainted:    true,
+			Address:      resourceAddress,
+			UpdateType:   ForceReplaceResource,
+			Tainted:      true,
+			ActionReason: ReplaceBecauseTainted,
 		}
 	} else if strings.HasSuffix(comment, RESOURCE_REPLACED) {
 		resourceAddress = parseResourceAddressFromComment(comment, RESOURCE_REPLACED)
 
 		rc = &ResourceChange{
-			Address:    resourceAddress,
-			UpdateType: ForceReplaceResource,
+			Address:      resourceAddress,
+			UpdateType:   ForceReplaceResource,
+			ActionReason: ReplaceBecauseCannotUpdate,
+		}
+	} else if strings.HasSuffix(comment, RESOURCE_REPLACED_BY_REQUEST) {
+		resourceAddress = parseResourceAddressFromComment(comment, RESOURCE_REPLACED_BY_REQUEST)
+
+		rc = &ResourceChange{
+			Address:      resourceAddress,
+			UpdateType:   ForceReplaceResource,
+			ActionReason: ReplaceByRequest,
+		}
+	} else if strings.HasSuffix(comment, RESOURCE_REPLACED_BY_TRIGGERS) {
+		resourceAddress = parseResourceAddressFromComment(comment, RESOURCE_REPLACED_BY_TRIGGERS)
+
+		rc = &ResourceChange{
+			Address:      resourceAddress,
+			UpdateType:   ForceReplaceResource,
+			ActionReason: ReplaceByTriggers,
 		}
 	} else if strings.HasSuffix(comment, RESOURCE_DESTROYED) {
 		resourceAddress = parseResourceAddressFromComment(comment, RESOURCE_DESTROYED)
@@ -119,12 +243,52 @@ func NewResourceChangeFromComment(comment string) (*ResourceChange, error) {
 			Address:    resourceAddress,
 			UpdateType: DestroyResource,
 		}
+	} else if strings.HasSuffix(comment, RESOURCE_IMPORTED) {
+		resourceAddress = parseResourceAddressFromComment(comment, RESOURCE_IMPORTED)
+
+		rc = &ResourceChange{
+			Address:    resourceAddress,
+			UpdateType: ImportResource,
+		}
+	} else if strings.HasSuffix(comment, RESOURCE_FORGOTTEN) {
+		resourceAddress = parseResourceAddressFromComment(comment, RESOURCE_FORGOTTEN)
+
+		rc = &ResourceChange{
+			Address:    resourceAddress,
+			UpdateType: ForgetResource,
+		}
+	} else if strings.HasSuffix(comment, RESOURCE_FORGOTTEN_OPENTOFU) {
+		resourceAddress = parseResourceAddressFromComment(comment, RESOURCE_FORGOTTEN_OPENTOFU)
+
+		rc = &ResourceChange{
+			Address:    resourceAddress,
+			UpdateType: ForgetResource,
+		}
+	} else if strings.HasSuffix(comment, RESOURCE_CHANGED_OUTSIDE) {
+		resourceAddress = parseResourceAddressFromComment(comment, RESOURCE_CHANGED_OUTSIDE)
+
+		rc = &ResourceChange{
+			Address:    resourceAddress,
+			UpdateType: ChangedOutsideResource,
+		}
+	} else if strings.HasSuffix(comment, RESOURCE_DELETED_OUTSIDE) {
+		resourceAddress = parseResourceAddressFromComment(comment, RESOURCE_DELETED_OUTSIDE)
+
+		rc = &ResourceChange{
+			Address:    resourceAddress,
+			UpdateType: DeletedOutsideResource,
+		}
 	}
 
 	if rc == nil {
 		return nil, fmt.Errorf("unknown comment line %s", comment)
 	}
 
+	if matches := resourceDeposedRegexp.FindStringSubmatch(rc.Address); matches != nil {
+		rc.Address = matches[1]
+		rc.DeposedKey = matches[2]
+	}
+
 	if err := rc.finalizeResourceInfo(); err != nil {
 		return nil, err
 	}
@@ -132,90 +296,158 @@ func NewResourceChangeFromComment(comment string) (*ResourceChange, error) {
 	return rc, nil
 }
 
-func (rc *ResourceChange) finalizeResourceInfo() error {
-	var address string
+// parseResourceChangeLine reads the change symbol and mode from the resource change line
+// The symbol must agree with the UpdateType, and the mode with the address, from the resource comment line
+func (rc *ResourceChange) parseResourceChangeLine(line string) error {
+	matches := resourceChangeLineRegexp.FindStringSubmatch(strings.TrimSpace(line))
+	if matches == nil {
+		return fmt.Errorf("%s is not a valid resource change line", line)
+	}
 
-	// parse index first in case the index contains a "."
-	addressIndex := strings.Split(rc.Address, "[")
-	address = addressIndex[0]
+	symbol := matches[1]
+	valid := false
+	for _, s := range resourceChangeSymbols[rc.UpdateType] {
+		if s == symbol {
+			valid = true
+		}
+	}
+	if !valid {
+		return fmt.Errorf("resource change symbol %q does not match the update type %s of %s", symbol, rc.UpdateType, rc.Address)
+	}
 
-	if len(addressIndex) == 2 {
-		index := dequote(strings.TrimSuffix(addressIndex[1], "]"))
+	mode := ManagedResourceMode
+	if matches[2] == "data" {
+		mode = DataResourceMode
+	}
+	if mode != rc.Mode {
+		return fmt.Errorf("resource change line %q does not match the mode %s of %s", strings.TrimSpace(line), rc.Mode, rc.Address)
+	}
 
-		if i, err := strconv.Atoi(index); err == nil {
-			rc.Index = i
-		} else {
-			rc.Index = index
-		}
-	} else if len(addressIndex) > 2 {
-		return fmt.Errorf("failed to parse resource info from address %s", rc.Address)
+	switch symbol {
+	case "-/+":
+		rc.ReplacementOrder = DestroyBeforeCreate
+	case "+/-":
+		rc.ReplacementOrder = CreateBeforeDestroy
 	}
 
-	values := strings.Split(address, ".")
+	return nil
+}
 
-	// TODO: handle module.module_name.data.type.name better
-	// TODO: eventually do something with "data"
-	// For now, since we're not handling it, we can just remove it
-	for k, v := range values {
-		var previous string
-		if k != 0 {
-			previous = values[k-1]
-		}
+// IsResourceAnnotationLine returns true if the line adds detail to the preceding resource comment line
+// Example: # (moved from aws_instance.a)
+func IsResourceAnnotationLine(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return strings.HasPrefix(trimmed, "# (") && strings.HasSuffix(trimmed, ")") && !IsUnchangedHiddenLine(trimmed)
+}
+
+// parseAnnotationLine records the detail from a resource annotation line
+// Annotations that are not understood are ignored
+func (rc *ResourceChange) parseAnnotationLine(line string) error {
+	trimmed := strings.TrimSpace(line)
+	if !IsResourceAnnotationLine(trimmed) {
+		return fmt.Errorf("%s is not a valid resource annotation line", line)
+	}
 
-		// don't remove "data" if any of the conditions are true:
-		// 1. Previous element was "module" or "data" (this means the module or data itself is named "data")
-		// 2. There are less than 2 elements left to parse (this means the resource name or type is "data")
-		if v == "data" && (previous != "module" && previous != "data") && (len(values)-k) > 2 {
-			values = append(values[:k], values[k+1:]...)
+	switch {
+	case strings.HasPrefix(trimmed, RESOURCE_MOVED_FROM):
+		rc.PreviousAddress = strings.TrimSuffix(strings.TrimPrefix(trimmed, RESOURCE_MOVED_FROM), ")")
+	case strings.HasPrefix(trimmed, RESOURCE_IMPORTED_FROM):
+		rc.ImportID = dequote(strings.TrimSuffix(strings.TrimPrefix(trimmed, RESOURCE_IMPORTED_FROM), ")"))
+	default:
+		text := strings.TrimSuffix(strings.TrimPrefix(trimmed, "# ("), ")")
+		if reason := newActionReasonFromAnnotation(text); reason != NoActionReason {
+			rc.ActionReason = reason
+			rc.ActionReasonText = text
 		}
 	}
 
-	if len(values) == 2 {
-		rc.Name = values[1]
-		rc.Type = values[0]
-	} else if len(values) > 2 {
-		rc.Name = values[len(values)-1]
-		rc.Type = values[len(values)-2]
-		rc.ModuleAddress = fmt.Sprintf("%s.%s", values[0], values[1])
-	} else {
-		return fmt.Errorf("failed to parse resource info from address %s", rc.Address)
+	return nil
+}
+
+// IsMoved returns true if the resource was moved from a different address
+func (rc *ResourceChange) IsMoved() bool {
+	return rc.PreviousAddress != ""
+}
+
+// IsImported returns true if the resource will be imported, with or without other changes
+func (rc *ResourceChange) IsImported() bool {
+	return rc.UpdateType == ImportResource || rc.ImportID != ""
+}
+
+// IsDeposed returns true if the change is for a deposed object rather than the current object of the resource
+func (rc *ResourceChange) IsDeposed() bool {
+	return rc.DeposedKey != ""
+}
+
+// IsDataSource returns true if the resource is a data source
+func (rc *ResourceChange) IsDataSource() bool {
+	return rc.Mode == DataResourceMode
+}
+
+// IsDrift returns true if the resource was changed or deleted outside of terraform
+func (rc *ResourceChange) IsDrift() bool {
+	return rc.UpdateType == ChangedOutsideResource || rc.UpdateType == DeletedOutsideResource
+}
+
+func (rc *ResourceChange) finalizeResourceInfo() error {
+	address, err := ParseAddress(rc.Address)
+	if err != nil {
+		return fmt.Errorf("failed to parse resource info from address %s: %s", rc.Address, err)
 	}
 
+	rc.ModuleAddress = address.ModuleAddress()
+	rc.Mode = address.Mode
+	rc.Type = address.Type
+	rc.Name = address.Name
+	rc.Index = address.Key
+
 	return nil
 }
 
-func (rc *ResourceChange) GetBeforeResource(opts ...GetBeforeAfterOptions) map[string]interface{} {
-	result := map[string]interface{}{}
+// ParsedAddress returns the parsed absolute address of the resource
+func (rc *ResourceChange) ParsedAddress() (*Address, error) {
+	return ParseAddress(rc.Address)
+}
 
-attrs:
-	for _, a := range rc.AttributeChanges {
-		for _, opt := range opts {
-			if opt(a) {
-				continue attrs
-			}
+// ReplacementCauses returns the paths of the attributes marked with "# forces replacement"
+// Paths are formatted the same way as Path.String
+// Example: ["ami", "root_block_device[0].volume_size", "ingress[0]"]
+func (rc *ResourceChange) ReplacementCauses() []string {
+	result := []string{}
+	Walk(rc, func(path Path, a Attr) error {
+		if a.GetForcesReplacement() {
+			result = append(result, path.String())
 		}
-		result[a.GetName()] = a.GetBefore(opts...)
-	}
+		return nil
+	})
 
 	return result
 }
 
+func (rc *ResourceChange) GetBeforeResource(opts ...GetBeforeAfterOptions) map[string]interface{} {
+	return getAttrsBefore(rc.AttributeChanges, opts...)
+}
+
 func (rc *ResourceChange) GetAfterResource(opts ...GetBeforeAfterOptions) map[string]interface{} {
-	result := map[string]interface{}{}
+	return getAttrsAfter(rc.AttributeChanges, opts...)
+}
 
-attrs:
-	for _, a := range rc.AttributeChanges {
-		for _, opt := range opts {
-			if opt(a) {
-				continue attrs
-			}
-		}
-		result[a.GetName()] = a.GetAfter(opts...)
-	}
+// GetBeforeResourceValue returns the resource before the planned changes as a Value of the map kind
+func (rc *ResourceChange) GetBeforeResourceValue(opts ...GetBeforeAfterOptions) Value {
+	return ValueOf(rc.GetBeforeResource(opts...))
+}
 
-	return result
+// GetAfterResourceValue returns the resource after the planned changes as a Value of the map kind
+func (rc *ResourceChange) GetAfterResourceValue(opts ...GetBeforeAfterOptions) Value {
+	return ValueOf(rc.GetAfterResource(opts...))
 }
 
 func parseResourceAddressFromComment(comment, updateText string) string {
 	return strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(comment, "# "), updateText))
 }
+
+// Attribute returns the attribute at the path, such as metadata.annotations["example.com/name"]
+// Refer to ParsePath for the format of the path
+func (rc *ResourceChange) Attribute(path string) (Attr, error) {
+	return findAttr(rc.AttributeChanges, false, path)
+}
@@ -46,6 +46,22 @@ func TestIsResourceCommentLine(t *testing.T) {
 			line:     "    # resource.path will be created",
 			expected: true,
 		},
+		"unchanged attributes hidden": {
+			line:     "# (3 unchanged attributes hidden)",
+			expected: false,
+		},
+		"resource moved": {
+			line:     "# resource.old has moved to resource.path",
+			expected: true,
+		},
+		"resource moved annotation": {
+			line:     "# (moved from resource.old)",
+			expected: false,
+		},
+		"unknown comment": {
+			line:     "# this is not a resource",
+			expected: false,
+		},
 		"other line": {
 			line:     "~ resource",
 			expected: false,
@@ -60,6 +76,57 @@ func TestIsResourceCommentLine(t *testing.T) {
 	}
 }
 
+func TestIsResourceChangeLine(t *testing.T) {
+	cases := map[string]struct {
+		line     string
+		expected bool
+	}{
+		"empty line": {
+			line:     "",
+			expected: false,
+		},
+		"resource created": {
+			line:     `+ resource "type" "name" {`,
+			expected: true,
+		},
+		"data source read": {
+			line:     `<= data "type" "name" {`,
+			expected: true,
+		},
+		"data source read before terraform v1.3": {
+			line:     `<= data "type" "name"  {`,
+			expected: true,
+		},
+		"resource replaced": {
+			line:     `-/+ resource "type" "name" {`,
+			expected: true,
+		},
+		"resource replaced with create before destroy": {
+			line:     `+/- resource "type" "name" {`,
+			expected: true,
+		},
+		"handles extra spaces": {
+			line:     `    ~ resource "type" "name" {`,
+			expected: true,
+		},
+		"block starting with resource": {
+			line:     `+ resource_policy {`,
+			expected: false,
+		},
+		"other line": {
+			line:     `+ attribute = "resource"`,
+			expected: false,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := IsResourceChangeLine(tc.line); got != tc.expected {
+				t.Errorf("Expected: %v but got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
 func TestNewResourceChangeFromComment(t *testing.T) {
 	cases := map[string]struct {
 		line        string
@@ -76,6 +143,7 @@ func TestNewResourceChangeFromComment(t *testing.T) {
 			shouldError: false,
 			expected: &ResourceChange{
 				Address:    "resource.path",
+				Mode:       ManagedResourceMode,
 				Type:       "resource",
 				Name:       "path",
 				UpdateType: NewResource,
@@ -86,6 +154,7 @@ func TestNewResourceChangeFromComment(t *testing.T) {
 			shouldError: false,
 			expected: &ResourceChange{
 				Address:    "resource.path",
+				Mode:       ManagedResourceMode,
 				Type:       "resource",
 				Name:       "path",
 				UpdateType: ReadResource,
@@ -101,6 +170,7 @@ func TestNewResourceChangeFromComment(t *testing.T) {
 			shouldError: false,
 			expected: &ResourceChange{
 				Address:    "resource.path",
+				Mode:       ManagedResourceMode,
 				Type:       "resource",
 				Name:       "path",
 				UpdateType: UpdateInPlaceResource,
@@ -109,32 +179,128 @@ func TestNewResourceChangeFromComment(t *testing.T) {
 		"resource tainted": {
 			line:        "# resource.path is tainted, so must be replaced",
 			shouldError: false,
+			expected: &ResourceChange{
+				Address:      "resource.path",
+				Mode:         ManagedResourceMode,
+				Type:         "resource",
+				Name:         "path",
+				UpdateType:   ForceReplaceResource,
+				Tainted:      true,
+				ActionReason: ReplaceBecauseTainted,
+			},
+		},
+		"resource replaced": {
+			line:        "# resource.path must be replaced",
+			shouldError: false,
+			expected: &ResourceChange{
+				Address:      "resource.path",
+				Mode:         ManagedResourceMode,
+				Type:         "resource",
+				Name:         "path",
+				UpdateType:   ForceReplaceResource,
+				ActionReason: ReplaceBecauseCannotUpdate,
+			},
+		},
+		"resource replaced as requested": {
+			line:        "# resource.path will be replaced, as requested",
+			shouldError: false,
+			expected: &ResourceChange{
+				Address:      "resource.path",
+				Mode:         ManagedResourceMode,
+				Type:         "resource",
+				Name:         "path",
+				UpdateType:   ForceReplaceResource,
+				ActionReason: ReplaceByRequest,
+			},
+		},
+		"resource replaced by triggers": {
+			line:        "# resource.path will be replaced due to changes in replace_triggered_by",
+			shouldError: false,
+			expected: &ResourceChange{
+				Address:      "resource.path",
+				Mode:         ManagedResourceMode,
+				Type:         "resource",
+				Name:         "path",
+				UpdateType:   ForceReplaceResource,
+				ActionReason: ReplaceByTriggers,
+			},
+		},
+		"resource destroyed": {
+			line:        "# resource.path will be destroyed",
+			shouldError: false,
 			expected: &ResourceChange{
 				Address:    "resource.path",
+				Mode:       ManagedResourceMode,
 				Type:       "resource",
 				Name:       "path",
-				UpdateType: ForceReplaceResource,
-				Tainted:    true,
+				UpdateType: DestroyResource,
 			},
 		},
-		"resource replaced": {
-			line:        "# resource.path must be replaced",
+		"resource changed outside of terraform": {
+			line:        "# resource.path has changed",
 			shouldError: false,
 			expected: &ResourceChange{
 				Address:    "resource.path",
+				Mode:       ManagedResourceMode,
 				Type:       "resource",
 				Name:       "path",
-				UpdateType: ForceReplaceResource,
+				UpdateType: ChangedOutsideResource,
 			},
 		},
-		"resource destroyed": {
-			line:        "# resource.path will be destroyed",
+		"resource deleted outside of terraform": {
+			line:        "# resource.path has been deleted",
 			shouldError: false,
 			expected: &ResourceChange{
 				Address:    "resource.path",
+				Mode:       ManagedResourceMode,
 				Type:       "resource",
 				Name:       "path",
-				UpdateType: DestroyResource,
+				UpdateType: DeletedOutsideResource,
+			},
+		},
+		"resource moved": {
+			line:        "# resource.old has moved to resource.path",
+			shouldError: false,
+			expected: &ResourceChange{
+				Address:         "resource.path",
+				PreviousAddress: "resource.old",
+				Mode:            ManagedResourceMode,
+				Type:            "resource",
+				Name:            "path",
+				UpdateType:      MoveResource,
+			},
+		},
+		"resource imported": {
+			line:        "# resource.path will be imported",
+			shouldError: false,
+			expected: &ResourceChange{
+				Address:    "resource.path",
+				Mode:       ManagedResourceMode,
+				Type:       "resource",
+				Name:       "path",
+				UpdateType: ImportResource,
+			},
+		},
+		"resource forgotten": {
+			line:        "# resource.path will no longer be managed by Terraform",
+			shouldError: false,
+			expected: &ResourceChange{
+				Address:    "resource.path",
+				Mode:       ManagedResourceMode,
+				Type:       "resource",
+				Name:       "path",
+				UpdateType: ForgetResource,
+			},
+		},
+		"resource forgotten by opentofu": {
+			line:        "# resource.path will no longer be managed by OpenTofu",
+			shouldError: false,
+			expected: &ResourceChange{
+				Address:    "resource.path",
+				Mode:       ManagedResourceMode,
+				Type:       "resource",
+				Name:       "path",
+				UpdateType: ForgetResource,
 			},
 		},
 		"handles extra spaces": {
@@ -142,6 +308,7 @@ func TestNewResourceChangeFromComment(t *testing.T) {
 			shouldError: false,
 			expected: &ResourceChange{
 				Address:    "resource.path",
+				Mode:       ManagedResourceMode,
 				Type:       "resource",
 				Name:       "path",
 				UpdateType: NewResource,
@@ -152,6 +319,7 @@ func TestNewResourceChangeFromComment(t *testing.T) {
 			shouldError: false,
 			expected: &ResourceChange{
 				Address:    "data.mydata.path",
+				Mode:       DataResourceMode,
 				Type:       "mydata",
 				Name:       "path",
 				UpdateType: ReadResource,
@@ -163,6 +331,7 @@ func TestNewResourceChangeFromComment(t *testing.T) {
 			expected: &ResourceChange{
 				Address:       "module.mymodule.resource.path",
 				ModuleAddress: "module.mymodule",
+				Mode:          ManagedResourceMode,
 				Type:          "resource",
 				Name:          "path",
 				UpdateType:    NewResource,
@@ -174,6 +343,7 @@ func TestNewResourceChangeFromComment(t *testing.T) {
 			expected: &ResourceChange{
 				Address:       `module.mymodule.resource.path["index"]`,
 				ModuleAddress: "module.mymodule",
+				Mode:          ManagedResourceMode,
 				Type:          "resource",
 				Name:          "path",
 				Index:         "index",
@@ -186,6 +356,7 @@ func TestNewResourceChangeFromComment(t *testing.T) {
 			expected: &ResourceChange{
 				Address:       `module.mymodule.resource.path["index@test.com"]`,
 				ModuleAddress: "module.mymodule",
+				Mode:          ManagedResourceMode,
 				Type:          "resource",
 				Name:          "path",
 				Index:         "index@test.com",
@@ -198,6 +369,7 @@ func TestNewResourceChangeFromComment(t *testing.T) {
 			expected: &ResourceChange{
 				Address:       "module.mymodule.resource.path[1]",
 				ModuleAddress: "module.mymodule",
+				Mode:          ManagedResourceMode,
 				Type:          "resource",
 				Name:          "path",
 				Index:         1,
@@ -210,6 +382,7 @@ func TestNewResourceChangeFromComment(t *testing.T) {
 			expected: &ResourceChange{
 				Address:       "module.mymodule.data.mydata.path",
 				ModuleAddress: "module.mymodule",
+				Mode:          DataResourceMode,
 				Type:          "mydata",
 				Name:          "path",
 				UpdateType:    ReadResource,
@@ -221,6 +394,7 @@ func TestNewResourceChangeFromComment(t *testing.T) {
 			expected: &ResourceChange{
 				Address:       "module.mymodule.data.mydata.path[0]",
 				ModuleAddress: "module.mymodule",
+				Mode:          DataResourceMode,
 				Type:          "mydata",
 				Name:          "path",
 				Index:         0,
@@ -233,11 +407,56 @@ func TestNewResourceChangeFromComment(t *testing.T) {
 			expected: &ResourceChange{
 				Address:       "module.mymodule.data.data.data",
 				ModuleAddress: "module.mymodule",
+				Mode:          DataResourceMode,
 				Type:          "data",
 				Name:          "data",
 				UpdateType:    ReadResource,
 			},
 		},
+		"nested modules": {
+			line:        "    # module.a.module.b.aws_s3_bucket.x will be created",
+			shouldError: false,
+			expected: &ResourceChange{
+				Address:       "module.a.module.b.aws_s3_bucket.x",
+				ModuleAddress: "module.a.module.b",
+				Mode:          ManagedResourceMode,
+				Type:          "aws_s3_bucket",
+				Name:          "x",
+				UpdateType:    NewResource,
+			},
+		},
+		"module instance key with a .": {
+			line:        `    # module.a["eu-west-1.b"].aws_s3_bucket.x["a[0]"] will be created`,
+			shouldError: false,
+			expected: &ResourceChange{
+				Address:       `module.a["eu-west-1.b"].aws_s3_bucket.x["a[0]"]`,
+				ModuleAddress: `module.a["eu-west-1.b"]`,
+				Mode:          ManagedResourceMode,
+				Type:          "aws_s3_bucket",
+				Name:          "x",
+				Index:         "a[0]",
+				UpdateType:    NewResource,
+			},
+		},
+		"deposed object": {
+			line:        "    # module.mymodule.resource.path[0] (deposed object 1a2b3c4d) will be destroyed",
+			shouldError: false,
+			expected: &ResourceChange{
+				Address:       "module.mymodule.resource.path[0]",
+				ModuleAddress: "module.mymodule",
+				Mode:          ManagedResourceMode,
+				Type:          "resource",
+				Name:          "path",
+				Index:         0,
+				DeposedKey:    "1a2b3c4d",
+				UpdateType:    DestroyResource,
+			},
+		},
+		"invalid address": {
+			line:        "    # resource will be created",
+			shouldError: true,
+			expected:    nil,
+		},
 		"other line": {
 			line:        "~ resource",
 			shouldError: true,
@@ -266,7 +485,7 @@ func TestGetBeforeResource(t *testing.T) {
 	}{
 		"one attribute": {
 			rc: &ResourceChange{
-				AttributeChanges: []attributeChange{
+				AttributeChanges: []Attr{
 					&AttributeChange{
 						Name:     "attribute",
 						OldValue: "oldValue",
@@ -280,7 +499,7 @@ func TestGetBeforeResource(t *testing.T) {
 		},
 		"multiple attribute": {
 			rc: &ResourceChange{
-				AttributeChanges: []attributeChange{
+				AttributeChanges: []Attr{
 					&AttributeChange{
 						Name:     "attribute1",
 						OldValue: "oldValue1",
@@ -300,10 +519,10 @@ func TestGetBeforeResource(t *testing.T) {
 		},
 		"map attribute": {
 			rc: &ResourceChange{
-				AttributeChanges: []attributeChange{
+				AttributeChanges: []Attr{
 					&MapAttributeChange{
 						Name: "map",
-						AttributeChanges: []attributeChange{
+						AttributeChanges: []Attr{
 							&AttributeChange{
 								Name:     "attribute1",
 								OldValue: "oldValue1",
@@ -327,7 +546,7 @@ func TestGetBeforeResource(t *testing.T) {
 		},
 		"map and normal attribute": {
 			rc: &ResourceChange{
-				AttributeChanges: []attributeChange{
+				AttributeChanges: []Attr{
 					&AttributeChange{
 						Name:     "attribute",
 						OldValue: "oldValue",
@@ -335,7 +554,7 @@ func TestGetBeforeResource(t *testing.T) {
 					},
 					&MapAttributeChange{
 						Name: "map",
-						AttributeChanges: []attributeChange{
+						AttributeChanges: []Attr{
 							&AttributeChange{
 								Name:     "attribute1",
 								OldValue: "oldValue1",
@@ -360,11 +579,11 @@ func TestGetBeforeResource(t *testing.T) {
 		},
 		"ignore sensitive values": {
 			rc: &ResourceChange{
-				AttributeChanges: []attributeChange{
+				AttributeChanges: []Attr{
 					&AttributeChange{
 						Name:     "attribute",
-						OldValue: "(sensitive value)",
-						NewValue: "(sensitive value)",
+						OldValue: SensitiveMarker,
+						NewValue: SensitiveMarker,
 					},
 					&AttributeChange{
 						Name:     "attribute2",
@@ -397,7 +616,7 @@ func TestGetAfterResource(t *testing.T) {
 	}{
 		"one attribute": {
 			rc: &ResourceChange{
-				AttributeChanges: []attributeChange{
+				AttributeChanges: []Attr{
 					&AttributeChange{
 						Name:     "attribute",
 						OldValue: "oldValue",
@@ -411,7 +630,7 @@ func TestGetAfterResource(t *testing.T) {
 		},
 		"multiple attribute": {
 			rc: &ResourceChange{
-				AttributeChanges: []attributeChange{
+				AttributeChanges: []Attr{
 					&AttributeChange{
 						Name:     "attribute1",
 						OldValue: "oldValue1",
@@ -431,10 +650,10 @@ func TestGetAfterResource(t *testing.T) {
 		},
 		"map attribute": {
 			rc: &ResourceChange{
-				AttributeChanges: []attributeChange{
+				AttributeChanges: []Attr{
 					&MapAttributeChange{
 						Name: "map",
-						AttributeChanges: []attributeChange{
+						AttributeChanges: []Attr{
 							&AttributeChange{
 								Name:     "attribute1",
 								OldValue: "oldValue1",
@@ -458,7 +677,7 @@ func TestGetAfterResource(t *testing.T) {
 		},
 		"map and normal attribute": {
 			rc: &ResourceChange{
-				AttributeChanges: []attributeChange{
+				AttributeChanges: []Attr{
 					&AttributeChange{
 						Name:     "attribute",
 						OldValue: "oldValue",
@@ -466,7 +685,7 @@ func TestGetAfterResource(t *testing.T) {
 					},
 					&MapAttributeChange{
 						Name: "map",
-						AttributeChanges: []attributeChange{
+						AttributeChanges: []Attr{
 							&AttributeChange{
 								Name:     "attribute1",
 								OldValue: "oldValue1",
@@ -491,11 +710,11 @@ func TestGetAfterResource(t *testing.T) {
 		},
 		"ignore sensitive values": {
 			rc: &ResourceChange{
-				AttributeChanges: []attributeChange{
+				AttributeChanges: []Attr{
 					&AttributeChange{
 						Name:     "attribute",
-						OldValue: "(sensitive value)",
-						NewValue: "(sensitive value)",
+						OldValue: SensitiveMarker,
+						NewValue: SensitiveMarker,
 					},
 					&AttributeChange{
 						Name:     "attribute2",
@@ -511,11 +730,11 @@ func TestGetAfterResource(t *testing.T) {
 		},
 		"ignore computed values": {
 			rc: &ResourceChange{
-				AttributeChanges: []attributeChange{
+				AttributeChanges: []Attr{
 					&AttributeChange{
 						Name:     "attribute",
 						OldValue: "oldValue",
-						NewValue: "(known after apply)",
+						NewValue: UnknownMarker,
 					},
 					&AttributeChange{
 						Name:     "attribute2",
@@ -531,11 +750,11 @@ func TestGetAfterResource(t *testing.T) {
 		},
 		"computed only": {
 			rc: &ResourceChange{
-				AttributeChanges: []attributeChange{
+				AttributeChanges: []Attr{
 					&AttributeChange{
 						Name:     "attribute",
 						OldValue: "oldValue",
-						NewValue: "(known after apply)",
+						NewValue: UnknownMarker,
 					},
 					&AttributeChange{
 						Name:     "attribute2",
@@ -545,7 +764,7 @@ func TestGetAfterResource(t *testing.T) {
 				},
 			},
 			expected: map[string]interface{}{
-				"attribute": "(known after apply)",
+				"attribute": UnknownMarker,
 			},
 			opts: []GetBeforeAfterOptions{ComputedOnly},
 		},
@@ -558,3 +777,113 @@ func TestGetAfterResource(t *testing.T) {
 		})
 	}
 }
+
+func TestGetAfterResourceValue(t *testing.T) {
+	cases := map[string]struct {
+		rc       *ResourceChange
+		expected Value
+		opts     []GetBeforeAfterOptions
+	}{
+		"computed and known values": {
+			rc: &ResourceChange{
+				AttributeChanges: []Attr{
+					&AttributeChange{
+						Name:     "attribute",
+						OldValue: "oldValue",
+						NewValue: UnknownMarker,
+					},
+					&AttributeChange{
+						Name:     "attribute2",
+						OldValue: 1,
+						NewValue: 2,
+					},
+				},
+			},
+			expected: ValueOf(map[string]interface{}{
+				"attribute":  UnknownMarker,
+				"attribute2": 2,
+			}),
+		},
+		"computed only": {
+			rc: &ResourceChange{
+				AttributeChanges: []Attr{
+					&AttributeChange{
+						Name:     "attribute",
+						OldValue: "oldValue",
+						NewValue: UnknownMarker,
+					},
+					&AttributeChange{
+						Name:     "attribute2",
+						OldValue: 1,
+						NewValue: 2,
+					},
+				},
+			},
+			expected: ValueOf(map[string]interface{}{
+				"attribute": UnknownMarker,
+			}),
+			opts: []GetBeforeAfterOptions{ComputedOnly},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := tc.rc.GetAfterResourceValue(tc.opts...); !reflect.DeepEqual(got, tc.expected) {
+				t.Fatalf("Expected: %v but got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestFilterResourceChanges(t *testing.T) {
+	managed := &ResourceChange{Address: "resource.path", Mode: ManagedResourceMode}
+	data := &ResourceChange{Address: "data.resource.path", Mode: DataResourceMode}
+	deposed := &ResourceChange{Address: "resource.path", Mode: ManagedResourceMode, DeposedKey: "1a2b3c4d"}
+
+	cases := map[string]struct {
+		rcs      []*ResourceChange
+		filters  []ResourceChangeFilter
+		expected []*ResourceChange
+	}{
+		"no resource changes": {
+			rcs:      []*ResourceChange{},
+			filters:  []ResourceChangeFilter{IgnoreDataSources},
+			expected: []*ResourceChange{},
+		},
+		"no filters": {
+			rcs:      []*ResourceChange{managed, data},
+			expected: []*ResourceChange{managed, data},
+		},
+		"ignore data sources": {
+			rcs:      []*ResourceChange{managed, data},
+			filters:  []ResourceChangeFilter{IgnoreDataSources},
+			expected: []*ResourceChange{managed},
+		},
+		"data sources only": {
+			rcs:      []*ResourceChange{managed, data},
+			filters:  []ResourceChangeFilter{DataSourcesOnly},
+			expected: []*ResourceChange{data},
+		},
+		"ignore deposed": {
+			rcs:      []*ResourceChange{managed, deposed},
+			filters:  []ResourceChangeFilter{IgnoreDeposed},
+			expected: []*ResourceChange{managed},
+		},
+		"deposed only": {
+			rcs:      []*ResourceChange{managed, data, deposed},
+			filters:  []ResourceChangeFilter{DeposedOnly},
+			expected: []*ResourceChange{deposed},
+		},
+		"all filtered": {
+			rcs:      []*ResourceChange{managed, data},
+			filters:  []ResourceChangeFilter{IgnoreDataSources, DataSourcesOnly},
+			expected: []*ResourceChange{},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := FilterResourceChanges(tc.rcs, tc.filters...); !reflect.DeepEqual(got, tc.expected) {
+				t.Fatalf("Expected: %v but got %v", tc.expected, got)
+			}
+		})
+	}
+}
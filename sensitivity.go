@@ -0,0 +1,100 @@
+package tfplanparse
+
+import (
+	"strings"
+)
+
+const (
+	// terraform v0.15+ warns about values and blocks that change sensitivity before the attribute or block
+	// Example: # Warning: this attribute value will be marked as sensitive and will not
+	SENSITIVITY_WARNING_PREFIX    = "# Warning: this "
+	SENSITIVITY_WARNING_SENSITIVE = " will be marked as sensitive"
+	SENSITIVITY_WARNING_NO_LONGER = " will no longer be marked as sensitive"
+	SENSITIVITY_WARNING_ATTRIBUTE = "attribute value"
+	SENSITIVITY_WARNING_BLOCK     = "block"
+)
+
+// sensitivityWarning is the change in sensitivity described by a warning comment
+type sensitivityWarning int
+
+const (
+	noSensitivityWarning sensitivityWarning = iota
+	// becomesSensitive is a value that is not sensitive before the change, but is sensitive after it
+	becomesSensitive
+	// becomesNonSensitive is a value that is sensitive before the change, but is not sensitive after it
+	becomesNonSensitive
+)
+
+// IsSensitivityWarningLine returns true if the line is a warning that the following attribute or block changes sensitivity
+// Example: # Warning: this attribute value will no longer be marked as sensitive
+func IsSensitivityWarningLine(line string) bool {
+	return newSensitivityWarningFromLine(line) != noSensitivityWarning
+}
+
+func newSensitivityWarningFromLine(line string) sensitivityWarning {
+	line = strings.TrimSpace(line)
+	if !strings.HasPrefix(line, SENSITIVITY_WARNING_PREFIX) {
+		return noSensitivityWarning
+	}
+
+	line = strings.TrimPrefix(line, SENSITIVITY_WARNING_PREFIX)
+	if !strings.HasPrefix(line, SENSITIVITY_WARNING_ATTRIBUTE) && !strings.HasPrefix(line, SENSITIVITY_WARNING_BLOCK) {
+		return noSensitivityWarning
+	}
+
+	switch {
+	case strings.Contains(line, SENSITIVITY_WARNING_NO_LONGER):
+		return becomesNonSensitive
+	case strings.Contains(line, SENSITIVITY_WARNING_SENSITIVE):
+		return becomesSensitive
+	}
+
+	return noSensitivityWarning
+}
+
+// apply records the change in sensitivity on the attribute or block following the warning
+// The warning takes precedence over the displayed value, since terraform hides the value on both sides of the change
+func (w sensitivityWarning) apply(a Attr) {
+	if w == noSensitivityWarning {
+		return
+	}
+
+	before, after := w == becomesNonSensitive, w == becomesSensitive
+	switch ac := a.(type) {
+	case *AttributeChange:
+		ac.BeforeSensitive, ac.AfterSensitive = before, after
+	case *BlockChange:
+		ac.BeforeSensitive, ac.AfterSensitive = before, after
+	case *MapAttributeChange:
+		ac.BeforeSensitive, ac.AfterSensitive = before, after
+	case *ArrayAttributeChange:
+		ac.BeforeSensitive, ac.AfterSensitive = before, after
+	}
+}
+
+// getSensitivity returns whether the value of the attribute is sensitive before and after the change
+func getSensitivity(a Attr) (bool, bool) {
+	switch ac := a.(type) {
+	case *AttributeChange:
+		return ac.BeforeSensitive, ac.AfterSensitive
+	case *BlockChange:
+		return ac.BeforeSensitive, ac.AfterSensitive
+	case *HeredocAttributeChange:
+		// the value before the change is displayed, so only the value after the change can be sensitive
+		return false, ac.Sensitive
+	case *JSONEncodeAttributeChange:
+		if ac.Sensitive {
+			return false, true
+		}
+	case *MapAttributeChange:
+		if ac.BeforeSensitive || ac.AfterSensitive {
+			return ac.BeforeSensitive, ac.AfterSensitive
+		}
+	case *ArrayAttributeChange:
+		if ac.BeforeSensitive || ac.AfterSensitive {
+			return ac.BeforeSensitive, ac.AfterSensitive
+		}
+	}
+
+	return a.IsSensitive(), a.IsSensitive()
+}
@@ -0,0 +1,51 @@
+package tfplanparse
+
+import (
+	"testing"
+)
+
+func TestNewSensitivityWarningFromLine(t *testing.T) {
+	cases := map[string]struct {
+		line     string
+		expected sensitivityWarning
+	}{
+		"empty line": {
+			line:     "",
+			expected: noSensitivityWarning,
+		},
+		"attribute becomes sensitive": {
+			line:     "# Warning: this attribute value will be marked as sensitive and will not",
+			expected: becomesSensitive,
+		},
+		"attribute is no longer sensitive": {
+			line:     "# Warning: this attribute value will no longer be marked as sensitive",
+			expected: becomesNonSensitive,
+		},
+		"block becomes sensitive": {
+			line:     "    # Warning: this block will be marked as sensitive and will not",
+			expected: becomesSensitive,
+		},
+		"block is no longer sensitive": {
+			line:     "# Warning: this block will no longer be marked as sensitive",
+			expected: becomesNonSensitive,
+		},
+		"second line of the warning": {
+			line:     "# display in UI output after applying this change.",
+			expected: noSensitivityWarning,
+		},
+		"other warning": {
+			line:     "# Warning: this resource will be destroyed",
+			expected: noSensitivityWarning,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := newSensitivityWarningFromLine(tc.line); got != tc.expected {
+				t.Fatalf("Expected: %v but got %v", tc.expected, got)
+			}
+			if got := IsSensitivityWarningLine(tc.line); got != (tc.expected != noSensitivityWarning) {
+				t.Fatalf("Expected IsSensitivityWarningLine to be %v", !got)
+			}
+		})
+	}
+}
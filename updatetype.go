@@ -9,4 +9,20 @@ const (
 	ForceReplaceResource  UpdateType = "forceReplace"
 	DestroyResource       UpdateType = "destroyed"
 	ReadResource          UpdateType = "read"
+	MoveResource          UpdateType = "moved"
+	ImportResource        UpdateType = "imported"
+	ForgetResource        UpdateType = "forgotten"
+
+	// ChangedOutsideResource and DeletedOutsideResource describe drift detected by terraform v0.15.4+
+	ChangedOutsideResource UpdateType = "changedOutside"
+	DeletedOutsideResource UpdateType = "deletedOutside"
+)
+
+type ReplacementOrder string
+
+const (
+	// DestroyBeforeCreate is the default order, shown as "-/+"
+	DestroyBeforeCreate ReplacementOrder = "destroyBeforeCreate"
+	// CreateBeforeDestroy is used for resources with create_before_destroy, shown as "+/-"
+	CreateBeforeDestroy ReplacementOrder = "createBeforeDestroy"
 )
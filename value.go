@@ -0,0 +1,410 @@
+package tfplanparse
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	NULL_VALUE = "null"
+)
+
+// Marker is stored in place of a value that is not shown in the plan output
+// Markers have their own type so they can't be mistaken for a string with the same text
+type Marker string
+
+const (
+	// UnknownMarker replaces values that are computed during apply
+	UnknownMarker Marker = COMPUTED_VALUE
+	// SensitiveMarker replaces values that are redacted because they are sensitive
+	SensitiveMarker Marker = SENSITIVE_VALUE
+)
+
+type ValueKind string
+
+const (
+	StringValue    ValueKind = "string"
+	NumberValue    ValueKind = "number"
+	BoolValue      ValueKind = "bool"
+	NullValue      ValueKind = "null"
+	ListValue      ValueKind = "list"
+	MapValue       ValueKind = "map"
+	UnknownValue   ValueKind = "unknown"
+	SensitiveValue ValueKind = "sensitive"
+)
+
+// Value is a typed attribute value
+// Use ValueOf to create a Value from the values returned by GetBefore and GetAfter
+type Value struct {
+	kind  ValueKind
+	value interface{}
+}
+
+// ValueOf creates a Value from a native value, such as the OldValue or NewValue of an AttributeChange
+// Values of unsupported types are converted to strings
+func ValueOf(v interface{}) Value {
+	switch val := v.(type) {
+	case Value:
+		return val
+	case nil:
+		return Value{kind: NullValue}
+	case Marker:
+		if val == SensitiveMarker {
+			return Value{kind: SensitiveValue}
+		}
+		return Value{kind: UnknownValue}
+	case string:
+		return Value{kind: StringValue, value: val}
+	case bool:
+		return Value{kind: BoolValue, value: val}
+	case int, int64, float64, Number:
+		return Value{kind: NumberValue, value: val}
+	case []interface{}:
+		list := make([]Value, 0, len(val))
+		for _, element := range val {
+			list = append(list, ValueOf(element))
+		}
+		return Value{kind: ListValue, value: list}
+	case map[string]interface{}:
+		m := make(map[string]Value, len(val))
+		for k, element := range val {
+			m[k] = ValueOf(element)
+		}
+		return Value{kind: MapValue, value: m}
+	}
+
+	return Value{kind: StringValue, value: fmt.Sprint(v)}
+}
+
+// Kind returns the kind of the value
+func (v Value) Kind() ValueKind {
+	return v.kind
+}
+
+// IsKnown returns false if the value is computed during apply
+func (v Value) IsKnown() bool {
+	return v.kind != UnknownValue
+}
+
+// IsSensitive returns true if the value is redacted because it is sensitive
+func (v Value) IsSensitive() bool {
+	return v.kind == SensitiveValue
+}
+
+// IsNull returns true if the value is null
+func (v Value) IsNull() bool {
+	return v.kind == NullValue
+}
+
+// AsString returns the value of a string
+func (v Value) AsString() (string, bool) {
+	s, ok := v.value.(string)
+	return s, ok && v.kind == StringValue
+}
+
+// AsBool returns the value of a bool
+func (v Value) AsBool() (bool, bool) {
+	b, ok := v.value.(bool)
+	return b, ok
+}
+
+// AsFloat64 returns the value of a number as a float64
+func (v Value) AsFloat64() (float64, bool) {
+	switch n := v.value.(type) {
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	case Number:
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// AsNumber returns the value of a number as a Number
+// Numbers that were not parsed with ExactNumbers are formatted as the shortest literal that represents them
+func (v Value) AsNumber() (Number, bool) {
+	switch n := v.value.(type) {
+	case int:
+		return Number(strconv.Itoa(n)), true
+	case int64:
+		return Number(strconv.FormatInt(n, 10)), true
+	case float64:
+		return Number(strconv.FormatFloat(n, 'g', -1, 64)), true
+	case Number:
+		return n, true
+	}
+	return "", false
+}
+
+// AsList returns the elements of a list
+func (v Value) AsList() ([]Value, bool) {
+	l, ok := v.value.([]Value)
+	return l, ok
+}
+
+// AsMap returns the elements of a map
+func (v Value) AsMap() (map[string]Value, bool) {
+	m, ok := v.value.(map[string]Value)
+	return m, ok
+}
+
+// Native returns the value as a native Go value
+// Unknown and sensitive values are returned as a Marker, and null values as nil
+func (v Value) Native() interface{} {
+	switch v.kind {
+	case UnknownValue:
+		return UnknownMarker
+	case SensitiveValue:
+		return SensitiveMarker
+	case ListValue:
+		list, _ := v.AsList()
+		result := make([]interface{}, 0, len(list))
+		for _, element := range list {
+			result = append(result, element.Native())
+		}
+		return result
+	case MapValue:
+		m, _ := v.AsMap()
+		result := make(map[string]interface{}, len(m))
+		for k, element := range m {
+			result[k] = element.Native()
+		}
+		return result
+	}
+
+	return v.value
+}
+
+// String returns the value in the same format as terraform
+func (v Value) String() string {
+	switch v.kind {
+	case NullValue:
+		return NULL_VALUE
+	case UnknownValue:
+		return COMPUTED_VALUE
+	case SensitiveValue:
+		return SENSITIVE_VALUE
+	case StringValue:
+		return strconv.Quote(v.value.(string))
+	case ListValue:
+		list, _ := v.AsList()
+		elements := []string{}
+		for _, element := range list {
+			elements = append(elements, element.String())
+		}
+		return "[" + strings.Join(elements, ", ") + "]"
+	case MapValue:
+		m, _ := v.AsMap()
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		elements := []string{}
+		for _, k := range keys {
+			elements = append(elements, strconv.Quote(k)+" = "+m[k].String())
+		}
+		return "{" + strings.Join(elements, ", ") + "}"
+	}
+
+	return fmt.Sprint(v.value)
+}
+
+// readAttributeDefinition splits an attribute line into the name and the value
+// Quoted names are unescaped, and may contain " = "
+// Example: ~ "Name" = "old" -> "new" -> "Name", `"old" -> "new"`
+func readAttributeDefinition(line string) (string, string, error) {
+	line = removeChangeTypeCharacters(strings.TrimSpace(line))
+	if strings.HasPrefix(line, `"`) {
+		name, rest, err := readString(line)
+		if err != nil {
+			return "", "", err
+		}
+		rest = strings.TrimLeft(rest, " ")
+		if !strings.HasPrefix(rest, "=") {
+			return "", "", fmt.Errorf("expected a \"=\" after the name in %s", line)
+		}
+		return name, strings.TrimSpace(strings.TrimPrefix(rest, "=")), nil
+	}
+
+	attribute := strings.SplitN(line, ATTRIBUTE_DEFINITON_DELIMITER, 2)
+	if len(attribute) != 2 {
+		return "", "", fmt.Errorf("expected a \"=\" after the name in %s", line)
+	}
+
+	return strings.TrimSpace(attribute[0]), strings.TrimSpace(attribute[1]), nil
+}
+
+// readAttributeValues reads the value of an attribute, which is either a single value,
+// or the value before and after the change delimited by " -> "
+// The returned bool is true if the input contains both values
+func readAttributeValues(input string, numberMode NumberMode) (interface{}, interface{}, bool, error) {
+	before, rest, err := readValue(input, numberMode)
+	if err != nil {
+		return nil, nil, false, err
+	}
+
+	rest = strings.TrimSpace(rest)
+	if rest == "" || rest == "," {
+		return before, before, false, nil
+	}
+	if !strings.HasPrefix(rest, strings.TrimSpace(ATTRIBUTE_CHANGE_DELIMITER)) {
+		return nil, nil, false, fmt.Errorf("unexpected %q after value in %s", rest, input)
+	}
+
+	after, rest, err := readValue(strings.TrimPrefix(rest, strings.TrimSpace(ATTRIBUTE_CHANGE_DELIMITER)), numberMode)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	if rest = strings.TrimSpace(rest); rest != "" && rest != "," {
+		return nil, nil, false, fmt.Errorf("unexpected %q after value in %s", rest, input)
+	}
+
+	return before, after, true, nil
+}
+
+// readValue reads a single value from the start of the input, and returns the value and the remaining input
+// Strings are unescaped, numbers are converted depending on the NumberMode, and null is converted to nil
+// "(known after apply)", "(sensitive value)" and "(sensitive)" are converted to a Marker, and values that are not understood are returned as they are
+func readValue(input string, numberMode NumberMode) (interface{}, string, error) {
+	input = strings.TrimLeft(input, " ")
+	switch {
+	case input == "":
+		return nil, "", fmt.Errorf("expected a value")
+	case strings.HasPrefix(input, `"`):
+		return readString(input)
+	case strings.HasPrefix(input, JSONENCODE_PREFIX):
+		// a value that terraform displays as jsonencode on a single line
+		// Example: jsonencode("x")
+		value, rest, err := readValue(strings.TrimPrefix(input, JSONENCODE_PREFIX), numberMode)
+		if err != nil {
+			return nil, "", err
+		}
+		if !strings.HasPrefix(rest, ")") {
+			return nil, "", fmt.Errorf("unterminated %s", input)
+		}
+		return value, rest[1:], nil
+	case strings.HasPrefix(input, "("):
+		end := strings.Index(input, ")")
+		if end == -1 {
+			return nil, "", fmt.Errorf("unterminated %s", input)
+		}
+		switch marker := input[:end+1]; marker {
+		case COMPUTED_VALUE:
+			return UnknownMarker, input[end+1:], nil
+		case SENSITIVE_VALUE, SENSITIVE_SHORT_VALUE:
+			return SensitiveMarker, input[end+1:], nil
+		default:
+			return marker, input[end+1:], nil
+		}
+	}
+
+	end := strings.IndexAny(input, " ,)")
+	if end == -1 {
+		end = len(input)
+	}
+	token, rest := input[:end], input[end:]
+
+	switch token {
+	case NULL_VALUE:
+		return nil, rest, nil
+	case "{}":
+		return map[string]interface{}{}, rest, nil
+	case "[]":
+		return []interface{}{}, rest, nil
+	case "true", "false":
+		return token == "true", rest, nil
+	}
+
+	if numberMode == ExactNumbers {
+		if isNumberLiteral(token) {
+			return Number(token), rest, nil
+		}
+		return token, rest, nil
+	}
+	if i, err := strconv.Atoi(token); err == nil {
+		return i, rest, nil
+	}
+	if f, err := strconv.ParseFloat(token, 64); err == nil {
+		return f, rest, nil
+	}
+
+	return token, rest, nil
+}
+
+// readString reads a quoted string from the start of the input, and returns the unescaped string and the remaining input
+func readString(input string) (string, string, error) {
+	if !strings.HasPrefix(input, `"`) {
+		return "", "", fmt.Errorf("expected a quoted string in %s", input)
+	}
+
+	for i := 1; i < len(input); i++ {
+		switch input[i] {
+		case '\\':
+			i++
+		case '"':
+			value, err := unescapeString(input[1:i])
+			if err != nil {
+				return "", "", fmt.Errorf("failed to read string %s: %s", input[:i+1], err)
+			}
+			return value, input[i+1:], nil
+		}
+	}
+
+	return "", "", fmt.Errorf("unterminated string %s", input)
+}
+
+// unescapeString replaces the escape sequences terraform uses when rendering strings
+// Ref: https://developer.hashicorp.com/terraform/language/expressions/strings#escape-sequences
+func unescapeString(input string) (string, error) {
+	var b strings.Builder
+
+	for i := 0; i < len(input); i++ {
+		switch {
+		case input[i] == '\\' && i+1 < len(input):
+			i++
+			switch input[i] {
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 't':
+				b.WriteByte('\t')
+			case '"', '\\':
+				b.WriteByte(input[i])
+			case 'u', 'U':
+				size := 4
+				if input[i] == 'U' {
+					size = 8
+				}
+				if i+size >= len(input) {
+					return "", fmt.Errorf("invalid unicode escape sequence")
+				}
+				r, err := strconv.ParseUint(input[i+1:i+1+size], 16, 32)
+				if err != nil || !utf8.ValidRune(rune(r)) {
+					return "", fmt.Errorf("invalid unicode escape sequence \\%s", input[i:i+1+size])
+				}
+				b.WriteRune(rune(r))
+				i += size
+			default:
+				b.WriteByte('\\')
+				b.WriteByte(input[i])
+			}
+		case strings.HasPrefix(input[i:], "$${"), strings.HasPrefix(input[i:], "%%{"):
+			// template sequences are escaped by doubling the first character
+			b.WriteByte(input[i])
+			i++
+		default:
+			b.WriteByte(input[i])
+		}
+	}
+
+	return b.String(), nil
+}
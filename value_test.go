@@ -0,0 +1,309 @@
+package tfplanparse
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestReadValue(t *testing.T) {
+	cases := map[string]struct {
+		input       string
+		numberMode  NumberMode
+		expected    interface{}
+		rest        string
+		shouldError bool
+	}{
+		"empty input": {
+			input:       "",
+			shouldError: true,
+		},
+		"string": {
+			input:    `"value" -> null`,
+			expected: "value",
+			rest:     " -> null",
+		},
+		"string containing an arrow and a delimiter": {
+			input:    `"a -> b = c"`,
+			expected: "a -> b = c",
+		},
+		"string with escaped quotes": {
+			input:    `"W/\"etag\""`,
+			expected: `W/"etag"`,
+		},
+		"string with escape sequences": {
+			input:    `"line\nnext\ttab\\ é \U0001F600"`,
+			expected: "line\nnext\ttab\\ é 😀",
+		},
+		"string with template escapes": {
+			input:    `"$${var.name} %%{if}"`,
+			expected: "${var.name} %{if}",
+		},
+		"unterminated string": {
+			input:       `"value`,
+			shouldError: true,
+		},
+		"invalid unicode escape": {
+			input:       `"\u00"`,
+			shouldError: true,
+		},
+		"int": {
+			input:    "1,",
+			expected: 1,
+			rest:     ",",
+		},
+		"negative int": {
+			input:    "-1",
+			expected: -1,
+		},
+		"float": {
+			input:    "1.23",
+			expected: 1.23,
+		},
+		"bool": {
+			input:    "false",
+			expected: false,
+		},
+		"null": {
+			input:    "null",
+			expected: nil,
+		},
+		"computed": {
+			input:    "(known after apply) -> null",
+			expected: UnknownMarker,
+			rest:     " -> null",
+		},
+		"sensitive": {
+			input:    "(sensitive value)",
+			expected: SensitiveMarker,
+		},
+		"short sensitive": {
+			input:    "(sensitive)",
+			expected: SensitiveMarker,
+		},
+		"other parenthesized text": {
+			input:    "(not a marker)",
+			expected: "(not a marker)",
+		},
+		"empty map": {
+			input:    "{}",
+			expected: map[string]interface{}{},
+		},
+		"empty list": {
+			input:    "[]",
+			expected: []interface{}{},
+		},
+		"exact mode does not read leading zeros as a number": {
+			input:      "012345678901 -> null",
+			numberMode: ExactNumbers,
+			expected:   "012345678901",
+			rest:       " -> null",
+		},
+		"exact float with an exponent": {
+			input:      "1e3,",
+			numberMode: ExactNumbers,
+			expected:   Number("1e3"),
+			rest:       ",",
+		},
+		"exact number that overflows an int": {
+			input:      "123456789012345678901234567890",
+			numberMode: ExactNumbers,
+			expected:   Number("123456789012345678901234567890"),
+		},
+		"exact mode does not read other tokens as numbers": {
+			input:      "Inf",
+			numberMode: ExactNumbers,
+			expected:   "Inf",
+		},
+		"unknown token": {
+			input:    "something",
+			expected: "something",
+		},
+		"jsonencode string": {
+			input:    `jsonencode("value") -> null`,
+			expected: "value",
+			rest:     " -> null",
+		},
+		"jsonencode number": {
+			input:    "jsonencode(1)",
+			expected: 1,
+		},
+		"jsonencode empty map": {
+			input:    "jsonencode({})",
+			expected: map[string]interface{}{},
+		},
+		"unterminated jsonencode": {
+			input:       `jsonencode("value"`,
+			shouldError: true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, rest, err := readValue(tc.input, tc.numberMode)
+			if err == nil && tc.shouldError {
+				t.Fatalf("Expected an error but didn't get one")
+			}
+			if err != nil && !tc.shouldError {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if tc.shouldError {
+				return
+			}
+
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Errorf("Expected: %v but got %v", tc.expected, got)
+			}
+			if rest != tc.rest {
+				t.Errorf("Expected rest: %q but got %q", tc.rest, rest)
+			}
+		})
+	}
+}
+
+func TestReadAttributeValues(t *testing.T) {
+	cases := map[string]struct {
+		input       string
+		before      interface{}
+		after       interface{}
+		changed     bool
+		shouldError bool
+	}{
+		"single value": {
+			input:  `"value"`,
+			before: "value",
+			after:  "value",
+		},
+		"changed value": {
+			input:   `"old" -> "new"`,
+			before:  "old",
+			after:   "new",
+			changed: true,
+		},
+		"changed value containing arrows": {
+			input:   `"a -> b" -> "c -> d"`,
+			before:  "a -> b",
+			after:   "c -> d",
+			changed: true,
+		},
+		"deleted value": {
+			input:   `1 -> null`,
+			before:  1,
+			after:   nil,
+			changed: true,
+		},
+		"array item": {
+			input:  `"value",`,
+			before: "value",
+			after:  "value",
+		},
+		"changed jsonencode value": {
+			input:   `jsonencode(1) -> jsonencode([])`,
+			before:  1,
+			after:   []interface{}{},
+			changed: true,
+		},
+		"trailing input": {
+			input:       `"old" "new"`,
+			shouldError: true,
+		},
+		"missing value after arrow": {
+			input:       `"old" ->`,
+			shouldError: true,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			before, after, changed, err := readAttributeValues(tc.input, NativeNumbers)
+			if err == nil && tc.shouldError {
+				t.Fatalf("Expected an error but didn't get one")
+			}
+			if err != nil && !tc.shouldError {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if tc.shouldError {
+				return
+			}
+
+			if !reflect.DeepEqual(before, tc.before) || !reflect.DeepEqual(after, tc.after) || changed != tc.changed {
+				t.Errorf("Expected: %v, %v, %v but got %v, %v, %v", tc.before, tc.after, tc.changed, before, after, changed)
+			}
+		})
+	}
+}
+
+func TestValueOf(t *testing.T) {
+	cases := map[string]struct {
+		input    interface{}
+		kind     ValueKind
+		expected string
+	}{
+		"null": {
+			input:    nil,
+			kind:     NullValue,
+			expected: "null",
+		},
+		"string": {
+			input:    "value",
+			kind:     StringValue,
+			expected: `"value"`,
+		},
+		"string with the text of a marker": {
+			input:    COMPUTED_VALUE,
+			kind:     StringValue,
+			expected: `"(known after apply)"`,
+		},
+		"int": {
+			input:    1,
+			kind:     NumberValue,
+			expected: "1",
+		},
+		"float": {
+			input:    1.5,
+			kind:     NumberValue,
+			expected: "1.5",
+		},
+		"exact number": {
+			input:    Number("123456789012345678901234567890"),
+			kind:     NumberValue,
+			expected: "123456789012345678901234567890",
+		},
+		"bool": {
+			input:    true,
+			kind:     BoolValue,
+			expected: "true",
+		},
+		"unknown": {
+			input:    UnknownMarker,
+			kind:     UnknownValue,
+			expected: COMPUTED_VALUE,
+		},
+		"sensitive": {
+			input:    SensitiveMarker,
+			kind:     SensitiveValue,
+			expected: SENSITIVE_VALUE,
+		},
+		"list": {
+			input:    []interface{}{"a", 1, UnknownMarker},
+			kind:     ListValue,
+			expected: `["a", 1, (known after apply)]`,
+		},
+		"map": {
+			input:    map[string]interface{}{"b": nil, "a": map[string]interface{}{}},
+			kind:     MapValue,
+			expected: `{"a" = {}, "b" = null}`,
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := ValueOf(tc.input)
+			if got.Kind() != tc.kind {
+				t.Errorf("Expected kind: %v but got %v", tc.kind, got.Kind())
+			}
+			if got.String() != tc.expected {
+				t.Errorf("Expected: %v but got %v", tc.expected, got.String())
+			}
+			if native := got.Native(); !reflect.DeepEqual(native, tc.input) {
+				t.Errorf("Expected native: %v but got %v", tc.input, native)
+			}
+		})
+	}
+}
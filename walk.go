@@ -0,0 +1,44 @@
+package tfplanparse
+
+import (
+	"errors"
+)
+
+// SkipChildren can be returned by a WalkFunc to skip the attributes nested in the current attribute
+var SkipChildren = errors.New("skip children")
+
+// WalkFunc is called by Walk for every attribute
+// Returning SkipChildren skips the attributes nested in a, and returning any other error stops the walk
+type WalkFunc func(path Path, a Attr) error
+
+// Walk calls fn for every attribute of the resource change, including the attributes nested in blocks, maps, arrays and jsonencode values
+// Attributes are visited in the order they appear in the plan output, and each attribute is visited before the attributes nested in it
+func Walk(rc *ResourceChange, fn WalkFunc) error {
+	steps := childSteps(rc.AttributeChanges, false)
+	for i, a := range rc.AttributeChanges {
+		if err := walkAttr(Path(steps[i]), a, fn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func walkAttr(path Path, a Attr, fn WalkFunc) error {
+	if err := fn(path, a); err != nil {
+		if err == SkipChildren {
+			return nil
+		}
+		return err
+	}
+
+	children, indexed := childAttrs(a)
+	steps := childSteps(children, indexed)
+	for i, child := range children {
+		if err := walkAttr(path.child(steps[i]...), child, fn); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,103 @@
+package tfplanparse
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWalk(t *testing.T) {
+	got, err := ParsePlanFromFile("test/forcesreplacement.stdout")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.ResourceChanges) != 1 {
+		t.Fatalf("Expected 1 resource change but got %d", len(got.ResourceChanges))
+	}
+	rc := got.ResourceChanges[0]
+
+	cases := map[string]struct {
+		skip     string
+		stopAt   string
+		expected []string
+	}{
+		"all attributes": {
+			expected: []string{
+				"ami",
+				"id",
+				"placement_group",
+				"security_groups",
+				"security_groups[0]",
+				"security_groups[0]",
+				"tags",
+				"tags.Name",
+				"ebs_block_device[0]",
+				"ebs_block_device[0].device_name",
+				"ebs_block_device[0].volume_size",
+				"ebs_block_device[0]",
+				"ebs_block_device[0].device_name",
+				"ebs_block_device[0].volume_size",
+				"root_block_device[0]",
+				"root_block_device[0].volume_size",
+			},
+		},
+		"skip children": {
+			skip: "security_groups",
+			expected: []string{
+				"ami",
+				"id",
+				"placement_group",
+				"security_groups",
+				"tags",
+				"tags.Name",
+				"ebs_block_device[0]",
+				"ebs_block_device[0].device_name",
+				"ebs_block_device[0].volume_size",
+				"ebs_block_device[0]",
+				"ebs_block_device[0].device_name",
+				"ebs_block_device[0].volume_size",
+				"root_block_device[0]",
+				"root_block_device[0].volume_size",
+			},
+		},
+		"stop on error": {
+			stopAt: "tags.Name",
+			expected: []string{
+				"ami",
+				"id",
+				"placement_group",
+				"security_groups",
+				"security_groups[0]",
+				"security_groups[0]",
+				"tags",
+				"tags.Name",
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			result := []string{}
+			err := Walk(rc, func(path Path, a Attr) error {
+				result = append(result, path.String())
+				switch path.String() {
+				case tc.skip:
+					return SkipChildren
+				case tc.stopAt:
+					return fmt.Errorf("stop")
+				}
+				return nil
+			})
+			if err == nil && tc.stopAt != "" {
+				t.Errorf("Expected an error but didn't get one")
+			}
+			if err != nil && tc.stopAt == "" {
+				t.Errorf("Unexpected error: %s", err)
+			}
+
+			if diff := cmp.Diff(result, tc.expected); diff != "" {
+				t.Errorf("(-got, +expected)\n%s", diff)
+			}
+		})
+	}
+}